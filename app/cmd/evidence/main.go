@@ -0,0 +1,115 @@
+package main
+
+import (
+	"app/internal/storage"
+	"app/pkg/protocol"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/caitlinelfring/go-env-default"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	StorageDir = env.GetDefault("STORAGE_DIR", "channels")
+)
+
+const usage = `usage:
+  evidence export <channel-id> [file]  export signed states of the stored channel, stdout is used without file
+  evidence verify <file>               verify exported evidence without a node`
+
+// Dispute evidence export and offline verification
+func main() {
+	if len(os.Args) < 3 {
+		log.Fatal(usage)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		output := ""
+		if len(os.Args) > 3 {
+			output = os.Args[3]
+		}
+		err = export(os.Args[2], output)
+	case "verify":
+		err = verify(os.Args[2])
+	default:
+		log.Fatal(usage)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// export writes evidence of the channel persisted by the server to the output file.
+func export(id, output string) error {
+	store, err := storage.NewFileStore(StorageDir)
+	if err != nil {
+		return err
+	}
+
+	record, err := store.Load(common.HexToHash(id).String())
+	if err != nil {
+		return err
+	}
+
+	if len(record.States) == 0 {
+		return errors.New("evidence: channel has no signed states")
+	}
+
+	evidence, err := protocol.NewEvidence(record.States[0].State.FixedPart())
+	if err != nil {
+		return err
+	}
+
+	for _, ss := range record.States {
+		if err := evidence.AddState(ss.State, ss.Signatures...); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(evidence, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = fmt.Println(string(data))
+		return err
+	}
+
+	return os.WriteFile(output, data, 0o644)
+}
+
+// verify checks evidence stored in the file and prints verified states.
+func verify(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var evidence protocol.Evidence
+	if err := json.Unmarshal(data, &evidence); err != nil {
+		return err
+	}
+
+	if err := evidence.Verify(); err != nil {
+		return err
+	}
+
+	fmt.Printf("channel %s: evidence is valid\n", evidence.ChannelID)
+	for _, s := range evidence.States {
+		fmt.Printf("turn %d final=%t state %s signed by", s.TurnNum, s.IsFinal, s.StateHash)
+		for _, signature := range s.Signatures {
+			fmt.Printf(" %s", signature.Signer)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"app/internal/parser"
+	"app/internal/server"
+	"app/internal/storage"
+	"app/pkg/nitro"
+	"app/pkg/protocol"
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/caitlinelfring/go-env-default"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	NodeUrl          = env.GetDefault("NODE_URL", "http://127.0.0.1:8545")
+	AccountsFileName = env.GetDefault("ACCOUNTS_FILENAME", "accounts.json")
+	Network          = env.GetDefault("NETWORK", "localhost")
+	ListenAddr       = env.GetDefault("LISTEN_ADDR", "127.0.0.1:8080")
+	StorageDir       = env.GetDefault("STORAGE_DIR", "channels")
+	AssetAddress     = common.HexToAddress("0x0")
+	DialTimeout      = 30 * time.Second
+)
+
+// HTTP API server exposing state channel operations.
+// The server isn't authenticated and signs with private keys of all accounts, so it listens on loopback by default
+// and must only be reachable from the local host.
+func main() {
+	myDir, err := os.Getwd()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	vaultAccount, err := parser.ToVaultAccount(myDir + "/../contracts/" + AccountsFileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	contractObj, err := parser.ToContract(myDir + "/../contracts/addresses.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var contractAddress string
+	for _, obj := range contractObj {
+		if obj[0].Name == Network {
+			contractAddress = obj[0].SC.NitroAdj.Address
+		}
+	}
+
+	keys := make(map[common.Address][]byte)
+	for _, vault := range vaultAccount.Accounts {
+		keys[common.HexToAddress(vault.Address)] = common.Hex2Bytes(strings.TrimPrefix(vault.PrivateKey, "0x"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DialTimeout)
+	client, err := nitro.NewClient(ctx, contractAddress, NodeUrl)
+	cancel()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	store, err := storage.NewFileStore(StorageDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	srv, err := server.New(protocol.NewContract(client, AssetAddress), store, keys)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("listening on %s", ListenAddr)
+	log.Fatal(http.ListenAndServe(ListenAddr, srv))
+}
@@ -4,6 +4,7 @@ import (
 	"app/internal/liability"
 	"app/pkg/eth/gasprice"
 	"app/pkg/protocol"
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -17,8 +18,8 @@ import (
 	"github.com/statechannels/go-nitro/crypto"
 )
 
-func Demo(participants []*protocol.Participant, privKeys map[*protocol.Participant][]byte, contract *protocol.Contract) error {
-	estimatedGasPrice, err := gasprice.Calculate(contract.Client.Eth)
+func Demo(ctx context.Context, participants []*protocol.Participant, privKeys map[*protocol.Participant][]byte, contract *protocol.Contract) error {
+	estimatedGasPrice, err := gasprice.Calculate(ctx, contract.Client.Eth)
 	if err != nil {
 		return err
 	}
@@ -30,7 +31,7 @@ func Demo(participants []*protocol.Participant, privKeys map[*protocol.Participa
 		return nil
 	}
 
-	err = fundChannel(ch, participants, privKeys, gasStation)
+	err = fundChannel(ctx, ch, participants, privKeys, gasStation)
 	if err != nil {
 		return nil
 	}
@@ -40,12 +41,12 @@ func Demo(participants []*protocol.Participant, privKeys map[*protocol.Participa
 		return nil
 	}
 
-	err = proposeState(ch, participants, privKeys)
+	err = proposeState(ctx, ch, participants, privKeys)
 	if err != nil {
 		return nil
 	}
 
-	err = concludeChannel(ch, participants, privKeys, gasStation)
+	err = concludeChannel(ctx, ch, participants, privKeys, gasStation)
 	if err != nil {
 		return nil
 	}
@@ -85,7 +86,7 @@ func initChannel(
 
 	}
 
-	fmt.Println("PreFund state has been completed\n")
+	fmt.Print("PreFund state has been completed\n\n")
 
 	return ch, nil
 }
@@ -96,7 +97,9 @@ func initialProposal(
 
 	prop := protocol.NewInitProposal(participants[0], contract)
 	for _, p := range participants[1:] {
-		prop.AddParticipant(p)
+		if err := prop.AddParticipant(p); err != nil {
+			return nil, err
+		}
 	}
 
 	fmt.Printf("%v\n\n", color.HiWhiteString("Initial data:"))
@@ -127,6 +130,7 @@ func initialProposal(
 }
 
 func fundChannel(
+	ctx context.Context,
 	ch *protocol.Channel,
 	participants []*protocol.Participant,
 	privKeys map[*protocol.Participant][]byte,
@@ -139,14 +143,14 @@ func fundChannel(
 	fmt.Println()
 
 	for _, p := range participants {
-		transaction, err := ch.FundChannel(p, privKeys[p], gasStation)
+		transaction, err := ch.FundChannel(ctx, p, privKeys[p], gasStation)
 		if err != nil {
 			return err
 		}
 		fmt.Printf("Funding channel by participant [%d] with amount [%d], transaction hash [%s] \n", p.Index, p.LockedAmount, transaction.Hash())
 		time.Sleep(time.Second * 10)
 	}
-	fmt.Println("Channel funding has been completed\n")
+	fmt.Print("Channel funding has been completed\n\n")
 
 	return nil
 }
@@ -173,12 +177,13 @@ func confirmChannelFund(
 	fmt.Println(color.GreenString("App Data: %v", ch.CurrentState().AppData))
 	fmt.Println(color.GreenString("Turn Number: %d", ch.CurrentState().TurnNum))
 	fmt.Println(color.GreenString("Is Final:  %v\n", ch.CurrentState().IsFinal))
-	fmt.Println("PostFund state has been completed\n")
+	fmt.Print("PostFund state has been completed\n\n")
 
 	return nil
 }
 
 func proposeState(
+	ctx context.Context,
 	ch *protocol.Channel,
 	participants []*protocol.Participant,
 	privKeys map[*protocol.Participant][]byte,
@@ -217,7 +222,7 @@ func proposeState(
 			fmt.Println(color.GreenString("Is Final:  %v\n", st.IsFinal()))
 
 			for p, pKey := range privKeys {
-				_, err := ch.SignState(st, pKey)
+				_, err := ch.SignState(ctx, st, pKey)
 				if err != nil {
 					return err
 				}
@@ -285,7 +290,7 @@ func proposeLiability(ch *protocol.Channel) ([]byte, bool, error) {
 
 		switch strings.ToLower(req) {
 		case "pending":
-			sp.PendingLiability(uint(fromNumber), uint(toNumber), liability.Asset(asset), amountNumber)
+			err = sp.PendingLiability(uint(fromNumber), uint(toNumber), liability.Asset(asset), amountNumber)
 		case "exec":
 			err = sp.ExecutedLiability(uint(fromNumber), uint(toNumber), liability.Asset(asset), amountNumber)
 		case "revert":
@@ -310,6 +315,7 @@ func proposeLiability(ch *protocol.Channel) ([]byte, bool, error) {
 }
 
 func concludeChannel(
+	ctx context.Context,
 	ch *protocol.Channel,
 	participants []*protocol.Participant,
 	privKeys map[*protocol.Participant][]byte,
@@ -325,7 +331,10 @@ func concludeChannel(
 	if err != nil {
 		return err
 	}
-	finalState.SetFinal()
+	err = finalState.SetFinal()
+	if err != nil {
+		return err
+	}
 
 	liabilityState, err := liability.DecodeFromBytes(finalState.AppData())
 
@@ -344,7 +353,7 @@ func concludeChannel(
 
 	participantSignatures := make(map[common.Address]crypto.Signature)
 	for p, pKey := range privKeys {
-		signature, err := ch.SignState(finalState, pKey)
+		signature, err := ch.SignState(ctx, finalState, pKey)
 		if err != nil {
 			return err
 		}
@@ -353,7 +362,7 @@ func concludeChannel(
 		participantSignatures[p.Address] = signature
 	}
 
-	transaction, err := ch.Conclude(participants[0], privKeys[participants[0]], participantSignatures, gasStation)
+	transaction, err := ch.Conclude(ctx, participants[0], privKeys[participants[0]], participantSignatures, gasStation)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,110 @@
+package examples
+
+import (
+	"app/pkg/apps/payments"
+	"app/pkg/eth/gasprice"
+	"app/pkg/protocol"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/crypto"
+)
+
+// StreamPayments opens SingleAssetPayments channel between payer and payee, streams micropayment
+// of the given amount on every payer turn and concludes the channel. Payee turns are skipped
+// with states which don't change the outcome.
+func StreamPayments(ctx context.Context, payer, payee *protocol.Participant, privKeys map[*protocol.Participant][]byte, contract *protocol.Contract, appDefinition common.Address, amount *big.Int, ticks int) error {
+	prop := protocol.NewInitProposal(payer, contract)
+	if err := prop.AddParticipant(payee); err != nil {
+		return err
+	}
+	prop.SetApp(appDefinition, payments.Codec{})
+
+	ch, err := protocol.InitChannel(prop, payer.Index)
+	if err != nil {
+		return err
+	}
+
+	participants := []*protocol.Participant{payer, payee}
+	for _, p := range participants {
+		_, err := ch.ApproveInitChannel(privKeys[p])
+		if err != nil {
+			return err
+		}
+	}
+
+	estimatedGasPrice, err := gasprice.Calculate(ctx, contract.Client.Eth)
+	if err != nil {
+		return err
+	}
+
+	gasStation := gasprice.Station{GasPrice: estimatedGasPrice}
+
+	for _, p := range participants {
+		_, err := ch.FundChannel(ctx, p, privKeys[p], gasStation)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, p := range participants {
+		_, err := ch.ApproveChannelFunding(privKeys[p])
+		if err != nil {
+			return err
+		}
+	}
+
+	for paid := 0; paid < ticks; {
+		st, err := ch.ProposeState()
+		if err != nil {
+			return err
+		}
+
+		// payer index in the channel is 0, so payer moves on even turns
+		if payments.Mover(st.TurnNum(), len(participants)) == 0 {
+			err = payments.Pay(st, 1, amount)
+			if err != nil {
+				return err
+			}
+			paid++
+		}
+
+		for _, p := range participants {
+			_, err := ch.SignState(ctx, st, privKeys[p])
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("Turn %d, allocations: payer %s, payee %s\n",
+			st.TurnNum(), st.Outcome()[0].Allocations[0].Amount, st.Outcome()[0].Allocations[1].Amount)
+	}
+
+	finalState, err := ch.ProposeState()
+	if err != nil {
+		return err
+	}
+	err = finalState.SetFinal()
+	if err != nil {
+		return err
+	}
+
+	participantSignatures := make(map[common.Address]crypto.Signature)
+	for _, p := range participants {
+		signature, err := ch.SignState(ctx, finalState, privKeys[p])
+		if err != nil {
+			return err
+		}
+
+		participantSignatures[p.Address] = signature
+	}
+
+	_, err = ch.Conclude(ctx, payer, privKeys[payer], participantSignatures, gasStation)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
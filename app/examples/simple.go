@@ -3,6 +3,7 @@ package examples
 import (
 	"app/pkg/eth/gasprice"
 	"app/pkg/protocol"
+	"context"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/statechannels/go-nitro/channel"
@@ -11,10 +12,12 @@ import (
 
 var MaxTurnNum = uint64(5)
 
-func Simple(participants []*protocol.Participant, privKeys map[*protocol.Participant][]byte, contract *protocol.Contract) error {
+func Simple(ctx context.Context, participants []*protocol.Participant, privKeys map[*protocol.Participant][]byte, contract *protocol.Contract) error {
 	prop := protocol.NewInitProposal(participants[0], contract)
 	for _, p := range participants[1:] {
-		prop.AddParticipant(p)
+		if err := prop.AddParticipant(p); err != nil {
+			return err
+		}
 	}
 
 	ch, err := protocol.InitChannel(prop, participants[0].Index)
@@ -29,7 +32,7 @@ func Simple(participants []*protocol.Participant, privKeys map[*protocol.Partici
 		}
 	}
 
-	estimatedGasPrice, err := gasprice.Calculate(contract.Client.Eth)
+	estimatedGasPrice, err := gasprice.Calculate(ctx, contract.Client.Eth)
 	if err != nil {
 		return err
 	}
@@ -37,7 +40,7 @@ func Simple(participants []*protocol.Participant, privKeys map[*protocol.Partici
 	gasStation := gasprice.Station{GasPrice: estimatedGasPrice}
 
 	for _, p := range participants {
-		_, err = ch.FundChannel(p, privKeys[p], gasStation)
+		_, err = ch.FundChannel(ctx, p, privKeys[p], gasStation)
 		if err != nil {
 			return err
 		}
@@ -58,7 +61,7 @@ func Simple(participants []*protocol.Participant, privKeys map[*protocol.Partici
 		}
 
 		for _, pKey := range privKeys {
-			_, err := ch.SignState(st, pKey)
+			_, err := ch.SignState(ctx, st, pKey)
 			if err != nil {
 				return err
 			}
@@ -69,11 +72,14 @@ func Simple(participants []*protocol.Participant, privKeys map[*protocol.Partici
 	if err != nil {
 		return err
 	}
-	finalState.SetFinal()
+	err = finalState.SetFinal()
+	if err != nil {
+		return err
+	}
 
 	participantSignatures := make(map[common.Address]crypto.Signature)
 	for p, pKey := range privKeys {
-		signature, err := ch.SignState(finalState, pKey)
+		signature, err := ch.SignState(ctx, finalState, pKey)
 		if err != nil {
 			return err
 		}
@@ -81,7 +87,7 @@ func Simple(participants []*protocol.Participant, privKeys map[*protocol.Partici
 		participantSignatures[p.Address] = signature
 	}
 
-	_, err = ch.Conclude(participants[0], privKeys[participants[0]], participantSignatures, gasStation)
+	_, err = ch.Conclude(ctx, participants[0], privKeys[participants[0]], participantSignatures, gasStation)
 	if err != nil {
 		return err
 	}
@@ -3,16 +3,19 @@ package examples
 import (
 	"app/pkg/eth/gasprice"
 	"app/pkg/protocol"
+	"context"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/shopspring/decimal"
 	"github.com/statechannels/go-nitro/crypto"
 )
 
-func SimpleTrade(participants []*protocol.Participant, privKeys map[*protocol.Participant][]byte, contract *protocol.Contract) error {
+func SimpleTrade(ctx context.Context, participants []*protocol.Participant, privKeys map[*protocol.Participant][]byte, contract *protocol.Contract) error {
 	prop := protocol.NewInitProposal(participants[0], contract)
 	for _, p := range participants[1:] {
-		prop.AddParticipant(p)
+		if err := prop.AddParticipant(p); err != nil {
+			return err
+		}
 	}
 
 	ch, err := protocol.InitChannel(prop, participants[0].Index)
@@ -27,7 +30,7 @@ func SimpleTrade(participants []*protocol.Participant, privKeys map[*protocol.Pa
 		}
 	}
 
-	estimatedGasPrice, err := gasprice.Calculate(contract.Client.Eth)
+	estimatedGasPrice, err := gasprice.Calculate(ctx, contract.Client.Eth)
 	if err != nil {
 		return err
 	}
@@ -35,7 +38,7 @@ func SimpleTrade(participants []*protocol.Participant, privKeys map[*protocol.Pa
 	gasStation := gasprice.Station{GasPrice: estimatedGasPrice}
 
 	for _, p := range participants {
-		_, err := ch.FundChannel(p, privKeys[p], gasStation)
+		_, err := ch.FundChannel(ctx, p, privKeys[p], gasStation)
 		if err != nil {
 			return err
 		}
@@ -53,8 +56,15 @@ func SimpleTrade(participants []*protocol.Participant, privKeys map[*protocol.Pa
 		return err
 	}
 
-	st.PendingLiability(participants[0].Index, participants[1].Index, "ETH", decimal.NewFromFloat(12))
-	st.PendingLiability(participants[1].Index, participants[0].Index, "BTC", decimal.NewFromFloat(0.2))
+	err = st.PendingLiability(participants[0].Index, participants[1].Index, "ETH", decimal.NewFromFloat(12))
+	if err != nil {
+		return err
+	}
+
+	err = st.PendingLiability(participants[1].Index, participants[0].Index, "BTC", decimal.NewFromFloat(0.2))
+	if err != nil {
+		return err
+	}
 
 	err = st.ApproveLiabilities()
 	if err != nil {
@@ -62,7 +72,7 @@ func SimpleTrade(participants []*protocol.Participant, privKeys map[*protocol.Pa
 	}
 
 	for _, pKey := range privKeys {
-		_, err := ch.SignState(st, pKey)
+		_, err := ch.SignState(ctx, st, pKey)
 		if err != nil {
 			return err
 		}
@@ -72,11 +82,14 @@ func SimpleTrade(participants []*protocol.Participant, privKeys map[*protocol.Pa
 	if err != nil {
 		return err
 	}
-	finalState.SetFinal()
+	err = finalState.SetFinal()
+	if err != nil {
+		return err
+	}
 
 	participantSignatures := make(map[common.Address]crypto.Signature)
 	for p, pKey := range privKeys {
-		signature, err := ch.SignState(finalState, pKey)
+		signature, err := ch.SignState(ctx, finalState, pKey)
 		if err != nil {
 			return err
 		}
@@ -84,7 +97,7 @@ func SimpleTrade(participants []*protocol.Participant, privKeys map[*protocol.Pa
 		participantSignatures[p.Address] = signature
 	}
 
-	_, err = ch.Conclude(participants[0], privKeys[participants[0]], participantSignatures, gasStation)
+	_, err = ch.Conclude(ctx, participants[0], privKeys[participants[0]], participantSignatures, gasStation)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,171 @@
+package asset
+
+import (
+	"app/internal/liability"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+)
+
+var (
+	ErrUnknownAsset      = errors.New("asset: asset is not registered")
+	ErrDuplicateAsset    = errors.New("asset: asset is already registered")
+	ErrInvalidDecimals   = errors.New("asset: decimals must not be negative")
+	ErrPrecisionLoss     = errors.New("asset: amount exceeds asset precision")
+	ErrAssetNotInChannel = errors.New("asset: asset is not part of the channel outcome")
+)
+
+// Rounding represents rule applied converting amount which can't be represented in on-chain units.
+type Rounding uint8
+
+const (
+	// RoundExact rejects amounts which can't be represented without precision loss.
+	RoundExact Rounding = iota
+	// RoundDown rounds amounts towards zero.
+	RoundDown
+	// RoundUp rounds amounts away from zero.
+	RoundUp
+	// RoundHalfEven rounds amounts to the nearest unit, ties to even.
+	RoundHalfEven
+)
+
+// Info stores information about asset symbol, token address, decimals and rounding rule.
+type Info struct {
+	Symbol   liability.Asset
+	Address  common.Address
+	Decimals int32
+	Rounding Rounding
+}
+
+// Registry maps liability asset symbols to on-chain tokens.
+type Registry struct {
+	mu     sync.RWMutex
+	assets map[liability.Asset]Info
+}
+
+// NewRegistry returns a new Registry with supplied assets.
+func NewRegistry(assets ...Info) (*Registry, error) {
+	r := &Registry{assets: make(map[liability.Asset]Info)}
+	for _, info := range assets {
+		err := r.Register(info)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Register adds asset to the registry.
+func (r *Registry) Register(info Info) error {
+	if info.Decimals < 0 {
+		return ErrInvalidDecimals
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.assets[info.Symbol]; found {
+		return ErrDuplicateAsset
+	}
+	r.assets[info.Symbol] = info
+
+	return nil
+}
+
+// Lookup returns information about asset with the given symbol.
+func (r *Registry) Lookup(symbol liability.Asset) (Info, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	info, found := r.assets[symbol]
+	if !found {
+		return Info{}, ErrUnknownAsset
+	}
+
+	return info, nil
+}
+
+// ToUnits converts human readable amount to on-chain units applying asset rounding rule.
+func (r *Registry) ToUnits(symbol liability.Asset, amount decimal.Decimal) (*big.Int, error) {
+	info, err := r.Lookup(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	units := amount.Shift(info.Decimals)
+	switch info.Rounding {
+	case RoundDown:
+		units = units.Truncate(0)
+	case RoundUp:
+		if !units.Equal(units.Truncate(0)) {
+			units = units.Truncate(0).Add(decimal.New(int64(units.Sign()), 0))
+		}
+	case RoundHalfEven:
+		units = units.RoundBank(0)
+	default:
+		if !units.Equal(units.Truncate(0)) {
+			return nil, ErrPrecisionLoss
+		}
+	}
+
+	return units.BigInt(), nil
+}
+
+// FromUnits converts on-chain units to human readable amount.
+func (r *Registry) FromUnits(symbol liability.Asset, units *big.Int) (decimal.Decimal, error) {
+	info, err := r.Lookup(symbol)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return decimal.NewFromBigInt(units, -info.Decimals), nil
+}
+
+// ValidateLiabilities checks that every liability asset is registered, is part of the outcome
+// and its amounts can be converted to on-chain units.
+func (r *Registry) ValidateLiabilities(ls liability.LiabilitiesState, exit outcome.Exit) error {
+	for _, liabilitiesMap := range ls {
+		for _, liabilities := range liabilitiesMap {
+			amounts := []map[liability.Asset]decimal.Decimal{liabilities.PendingAmounts(), liabilities.ExecutedAmounts()}
+			for _, assetAmounts := range amounts {
+				for symbol, amount := range assetAmounts {
+					err := r.validate(symbol, amount, exit)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks single liability amount against the outcome.
+func (r *Registry) validate(symbol liability.Asset, amount decimal.Decimal, exit outcome.Exit) error {
+	info, err := r.Lookup(symbol)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, singleAssetExit := range exit {
+		if singleAssetExit.Asset == info.Address {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return ErrAssetNotInChannel
+	}
+
+	_, err = r.ToUnits(symbol, amount)
+
+	return err
+}
@@ -0,0 +1,129 @@
+package asset
+
+import (
+	"app/internal/liability"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ethAddress = common.HexToAddress("0x")
+	usdAddress = common.HexToAddress("0x01")
+)
+
+func getRegistry(t *testing.T) *Registry {
+	registry, err := NewRegistry(
+		Info{Symbol: "ETH", Address: ethAddress, Decimals: 18},
+		Info{Symbol: "USD", Address: usdAddress, Decimals: 2, Rounding: RoundHalfEven},
+	)
+	assert.NoError(t, err)
+
+	return registry
+}
+
+func TestRegister(t *testing.T) {
+	registry := getRegistry(t)
+
+	t.Run("duplicate asset", func(t *testing.T) {
+		err := registry.Register(Info{Symbol: "ETH", Address: ethAddress, Decimals: 18})
+		assert.ErrorIs(t, err, ErrDuplicateAsset)
+	})
+
+	t.Run("negative decimals", func(t *testing.T) {
+		err := registry.Register(Info{Symbol: "BTC", Decimals: -1})
+		assert.ErrorIs(t, err, ErrInvalidDecimals)
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		info, err := registry.Lookup("USD")
+		assert.NoError(t, err)
+		assert.Equal(t, usdAddress, info.Address)
+
+		_, err = registry.Lookup("BTC")
+		assert.ErrorIs(t, err, ErrUnknownAsset)
+	})
+}
+
+func TestToUnits(t *testing.T) {
+	registry := getRegistry(t)
+
+	t.Run("exact conversion", func(t *testing.T) {
+		units, err := registry.ToUnits("ETH", decimal.RequireFromString("1.000000000000000001"))
+		assert.NoError(t, err)
+		assert.Equal(t, "1000000000000000001", units.String())
+	})
+
+	t.Run("precision loss", func(t *testing.T) {
+		_, err := registry.ToUnits("ETH", decimal.New(1, -19))
+		assert.ErrorIs(t, err, ErrPrecisionLoss)
+	})
+
+	t.Run("rounding rules", func(t *testing.T) {
+		amount := decimal.RequireFromString("0.125")
+		expected := map[Rounding]int64{RoundDown: 12, RoundUp: 13, RoundHalfEven: 12}
+
+		for rounding, units := range expected {
+			registry, err := NewRegistry(Info{Symbol: "USD", Decimals: 2, Rounding: rounding})
+			assert.NoError(t, err)
+
+			actual, err := registry.ToUnits("USD", amount)
+			assert.NoError(t, err)
+			assert.Equal(t, big.NewInt(units), actual)
+		}
+	})
+
+	t.Run("unknown asset", func(t *testing.T) {
+		_, err := registry.ToUnits("BTC", decimal.NewFromInt(1))
+		assert.ErrorIs(t, err, ErrUnknownAsset)
+	})
+}
+
+func TestFromUnits(t *testing.T) {
+	registry := getRegistry(t)
+
+	amount, err := registry.FromUnits("USD", big.NewInt(1234))
+	assert.NoError(t, err)
+	assert.True(t, amount.Equal(decimal.RequireFromString("12.34")))
+
+	units, err := registry.ToUnits("USD", amount)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1234), units)
+}
+
+func TestValidateLiabilities(t *testing.T) {
+	registry := getRegistry(t)
+	exit := outcome.Exit{{Asset: ethAddress}}
+
+	t.Run("valid liabilities", func(t *testing.T) {
+		ls := make(liability.LiabilitiesState)
+		ls.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(0.5))
+
+		assert.NoError(t, registry.ValidateLiabilities(ls, exit))
+	})
+
+	t.Run("unknown asset", func(t *testing.T) {
+		ls := make(liability.LiabilitiesState)
+		ls.AddPendingLiability(0, 1, "BTC", decimal.NewFromFloat(0.5))
+
+		assert.ErrorIs(t, registry.ValidateLiabilities(ls, exit), ErrUnknownAsset)
+	})
+
+	t.Run("asset is not in channel", func(t *testing.T) {
+		ls := make(liability.LiabilitiesState)
+		ls.AddPendingLiability(0, 1, "USD", decimal.NewFromFloat(0.5))
+
+		assert.ErrorIs(t, registry.ValidateLiabilities(ls, exit), ErrAssetNotInChannel)
+	})
+
+	t.Run("precision loss", func(t *testing.T) {
+		ls := make(liability.LiabilitiesState)
+		ls.AddPendingLiability(0, 1, "ETH", decimal.New(1, -19))
+
+		assert.ErrorIs(t, registry.ValidateLiabilities(ls, exit), ErrPrecisionLoss)
+	})
+}
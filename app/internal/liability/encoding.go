@@ -0,0 +1,330 @@
+package liability
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/shopspring/decimal"
+)
+
+// EncodingVersion is the version of canonical liabilities state encoding.
+const EncodingVersion uint8 = 1
+
+// MaxParticipants is the maximum number of channel participants accepted by ForceMove.
+const MaxParticipants = 255
+
+var (
+	ErrUnsupportedVersion = errors.New("liability: unsupported encoding version")
+	ErrInvalidEncoding    = errors.New("liability: encoding is not canonical")
+	ErrParticipantIndex   = errors.New("liability: participant index out of range")
+)
+
+// headerSize is the size of ABI encoded version word.
+const headerSize = 32
+
+var (
+	uint8Ty, _ = abi.NewType("uint8", "uint8", nil)
+
+	pendingTy, _ = abi.NewType("tuple[]", "struct Pending[]", []abi.ArgumentMarshaling{
+		{Name: "from", Type: "uint256"},
+		{Name: "to", Type: "uint256"},
+		{Name: "asset", Type: "string"},
+		{Name: "coefficient", Type: "int256"},
+		{Name: "exponent", Type: "int32"},
+		{Name: "reference", Type: "string"},
+		{Name: "turnNum", Type: "uint64"},
+		{Name: "timestamp", Type: "int64"},
+		{Name: "expiry", Type: "int64"},
+	})
+
+	executedTy, _ = abi.NewType("tuple[]", "struct Execution[]", []abi.ArgumentMarshaling{
+		{Name: "from", Type: "uint256"},
+		{Name: "to", Type: "uint256"},
+		{Name: "asset", Type: "string"},
+		{Name: "coefficient", Type: "int256"},
+		{Name: "exponent", Type: "int32"},
+		{Name: "turnNum", Type: "uint64"},
+		{Name: "reference", Type: "string"},
+		{Name: "timestamp", Type: "int64"},
+	})
+
+	// arguments describe ABI layout of encoded liabilities state, which can be read in Solidity as
+	// abi.decode(appData, (uint8, Pending[], Execution[])) where
+	// struct Pending { uint256 from; uint256 to; string asset; int256 coefficient; int32 exponent;
+	//                  string reference; uint64 turnNum; int64 timestamp; int64 expiry; }
+	// struct Execution { uint256 from; uint256 to; string asset; int256 coefficient; int32 exponent;
+	//                    uint64 turnNum; string reference; int64 timestamp; }
+	// and amount is coefficient * 10^exponent.
+	arguments = abi.Arguments{{Type: uint8Ty}, {Type: pendingTy}, {Type: executedTy}}
+)
+
+// encodedPending represents pending liability entry in ABI encoding.
+type encodedPending struct {
+	From        *big.Int
+	To          *big.Int
+	Asset       string
+	Coefficient *big.Int
+	Exponent    int32
+	Reference   string
+	TurnNum     uint64
+	Timestamp   int64
+	Expiry      int64
+}
+
+// encodedExecution represents liability execution in ABI encoding.
+type encodedExecution struct {
+	From        *big.Int
+	To          *big.Int
+	Asset       string
+	Coefficient *big.Int
+	Exponent    int32
+	TurnNum     uint64
+	Reference   string
+	Timestamp   int64
+}
+
+// EncodeToBytes tranform liabilitiesState struct to bytes.
+// Encoding is canonical: entries are sorted and amounts are normalized, so the same logical
+// state is always encoded to the same bytes. Pending entries and executions keep their ledger order.
+func (ls LiabilitiesState) EncodeToBytes() ([]byte, error) {
+	pending := []encodedPending{}
+	executed := []encodedExecution{}
+
+	for from, liabilitiesMap := range ls {
+		for to, liabilities := range liabilitiesMap {
+			for asset, entries := range liabilities.Pending {
+				for _, entry := range entries {
+					coefficient, exponent := normalize(entry.Amount)
+					pending = append(pending, encodedPending{
+						From: bigUint(from), To: bigUint(to), Asset: string(asset),
+						Coefficient: coefficient, Exponent: exponent,
+						Reference: entry.Reference, TurnNum: entry.TurnNum, Timestamp: entry.Timestamp, Expiry: entry.Expiry,
+					})
+				}
+			}
+
+			for asset, executions := range liabilities.Executed {
+				for _, execution := range executions {
+					coefficient, exponent := normalize(execution.Amount)
+					executed = append(executed, encodedExecution{
+						From: bigUint(from), To: bigUint(to), Asset: string(asset),
+						Coefficient: coefficient, Exponent: exponent,
+						TurnNum: execution.TurnNum, Reference: execution.Reference, Timestamp: execution.Timestamp,
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		a, b := pending[i], pending[j]
+		return lessEntry(a.From, a.To, a.Asset, b.From, b.To, b.Asset)
+	})
+
+	sort.SliceStable(executed, func(i, j int) bool {
+		a, b := executed[i], executed[j]
+		return lessEntry(a.From, a.To, a.Asset, b.From, b.To, b.Asset)
+	})
+
+	return arguments.Pack(EncodingVersion, pending, executed)
+}
+
+// DecodeFromBytes tranform bytes to liabilitiesState struct.
+// Only canonical encoding is accepted: data must be exactly the bytes EncodeToBytes returns for the decoded state,
+// participant indices must be lower than MaxParticipants and amounts must be positive.
+// Payloads of legacy gob encoding are decoded as well.
+func DecodeFromBytes(liabilityData []byte) (LiabilitiesState, error) {
+	if len(liabilityData) == 0 {
+		return LiabilitiesState{}, ErrEmptyByteArray
+	}
+
+	if !isVersioned(liabilityData) {
+		return decodeGob(liabilityData)
+	}
+
+	if liabilityData[headerSize-1] != EncodingVersion {
+		return LiabilitiesState{}, ErrUnsupportedVersion
+	}
+
+	values, err := arguments.Unpack(liabilityData)
+	if err != nil {
+		return LiabilitiesState{}, err
+	}
+
+	pending := *abi.ConvertType(values[1], new([]encodedPending)).(*[]encodedPending)
+	executed := *abi.ConvertType(values[2], new([]encodedExecution)).(*[]encodedExecution)
+	liabilitiesState := make(LiabilitiesState)
+
+	for _, e := range pending {
+		liabilities, err := liabilitiesState.decodedLiabilities(e.From, e.To, e.Coefficient)
+		if err != nil {
+			return LiabilitiesState{}, err
+		}
+
+		liabilities.Pending[Asset(e.Asset)] = append(liabilities.Pending[Asset(e.Asset)], PendingEntry{
+			Amount:    decimal.NewFromBigInt(e.Coefficient, e.Exponent),
+			Reference: e.Reference,
+			TurnNum:   e.TurnNum,
+			Timestamp: e.Timestamp,
+			Expiry:    e.Expiry,
+		})
+	}
+
+	for _, e := range executed {
+		liabilities, err := liabilitiesState.decodedLiabilities(e.From, e.To, e.Coefficient)
+		if err != nil {
+			return LiabilitiesState{}, err
+		}
+
+		liabilities.Executed[Asset(e.Asset)] = append(liabilities.Executed[Asset(e.Asset)], Execution{
+			Amount:    decimal.NewFromBigInt(e.Coefficient, e.Exponent),
+			TurnNum:   e.TurnNum,
+			Reference: e.Reference,
+			Timestamp: e.Timestamp,
+		})
+	}
+
+	canonical, err := liabilitiesState.EncodeToBytes()
+	if err != nil {
+		return LiabilitiesState{}, err
+	}
+
+	if !bytes.Equal(canonical, liabilityData) {
+		return LiabilitiesState{}, ErrInvalidEncoding
+	}
+
+	return liabilitiesState, nil
+}
+
+// ValidateParticipants returns ErrParticipantIndex if any liability refers to participant index
+// which is not lower than number of channel participants.
+func (ls LiabilitiesState) ValidateParticipants(participants uint) error {
+	for from, liabilitiesMap := range ls {
+		for to := range liabilitiesMap {
+			if from >= participants || to >= participants {
+				return fmt.Errorf("%w: liability from %d to %d in channel of %d participants", ErrParticipantIndex, from, to, participants)
+			}
+		}
+	}
+
+	return nil
+}
+
+// decodedLiabilities returns liabilities between decoded participants after checking indices and amount.
+func (ls LiabilitiesState) decodedLiabilities(from, to, coefficient *big.Int) (*Liabilities, error) {
+	for _, index := range []*big.Int{from, to} {
+		if !index.IsUint64() || index.Uint64() >= MaxParticipants {
+			return nil, fmt.Errorf("%w: %s", ErrParticipantIndex, index)
+		}
+	}
+
+	if coefficient.Sign() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	return ls.liabilities(uint(from.Uint64()), uint(to.Uint64())), nil
+}
+
+// liabilities returns liabilities between participants, creating them if they don't exist.
+func (ls LiabilitiesState) liabilities(from, to uint) *Liabilities {
+	if _, found := ls[from]; !found {
+		ls[from] = make(LiabilitiesMap)
+	}
+	if _, found := ls[from][to]; !found {
+		ls[from][to] = NewLiabilities()
+	}
+
+	return ls[from][to]
+}
+
+// lessEntry orders encoded entries by participants and asset.
+func lessEntry(fromA, toA *big.Int, assetA string, fromB, toB *big.Int, assetB string) bool {
+	if c := fromA.Cmp(fromB); c != 0 {
+		return c < 0
+	}
+	if c := toA.Cmp(toB); c != 0 {
+		return c < 0
+	}
+
+	return assetA < assetB
+}
+
+// bigUint returns participant index as big.Int.
+func bigUint(index uint) *big.Int {
+	return new(big.Int).SetUint64(uint64(index))
+}
+
+// normalize returns decimal coefficient and exponent without trailing zeros.
+func normalize(amount decimal.Decimal) (*big.Int, int32) {
+	coefficient, exponent := amount.Coefficient(), amount.Exponent()
+	if coefficient.Sign() == 0 {
+		return coefficient, 0
+	}
+
+	ten := big.NewInt(10)
+	quotient, remainder := new(big.Int), new(big.Int)
+	for {
+		quotient.QuoRem(coefficient, ten, remainder)
+		if remainder.Sign() != 0 {
+			return coefficient, exponent
+		}
+		coefficient.Set(quotient)
+		exponent++
+	}
+}
+
+// isVersioned returns true if data starts with ABI encoded version word.
+func isVersioned(data []byte) bool {
+	if len(data) < headerSize {
+		return false
+	}
+
+	for _, b := range data[:headerSize-1] {
+		if b != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// gobLiabilities is the layout of Liabilities encoded with legacy gob encoding.
+type gobLiabilities struct {
+	Pending  map[Asset]decimal.Decimal
+	Executed map[Asset]decimal.Decimal
+}
+
+// decodeGob decodes liabilities state encoded with legacy gob encoding.
+func decodeGob(liabilityData []byte) (LiabilitiesState, error) {
+	buf := bytes.NewBuffer(liabilityData)
+	dec := gob.NewDecoder(buf)
+	var legacyState map[uint]map[uint]*gobLiabilities
+
+	if err := dec.Decode(&legacyState); err != nil {
+		return LiabilitiesState{}, err
+	}
+
+	liabilitiesState := make(LiabilitiesState)
+	for from, legacyMap := range legacyState {
+		for to, legacy := range legacyMap {
+			if from >= MaxParticipants || to >= MaxParticipants {
+				return LiabilitiesState{}, fmt.Errorf("%w: liability from %d to %d", ErrParticipantIndex, from, to)
+			}
+
+			liabilities := liabilitiesState.liabilities(from, to)
+			for asset, amount := range legacy.Pending {
+				liabilities.AddPendingLiability(asset, amount)
+			}
+			for asset, amount := range legacy.Executed {
+				liabilities.Executed[asset] = []Execution{{Amount: amount}}
+			}
+		}
+	}
+
+	return liabilitiesState, nil
+}
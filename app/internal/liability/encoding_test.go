@@ -0,0 +1,205 @@
+package liability
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math/big"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeToBytes(t *testing.T) {
+	t.Run("deterministic encoding", func(t *testing.T) {
+		first := make(LiabilitiesState)
+		first.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(1))
+		first.AddPendingLiability(0, 1, "BTC", decimal.NewFromFloat(0.2))
+		first.AddPendingLiability(2, 0, "LTC", decimal.NewFromFloat(3))
+		first.AddPendingLiability(1, 2, "ETH", decimal.NewFromFloat(4))
+		err := first.AddExecutedLiability(1, 2, "ETH", Execution{Amount: decimal.NewFromFloat(1)})
+		assert.NoError(t, err)
+
+		second := make(LiabilitiesState)
+		second.AddPendingLiability(1, 2, "ETH", decimal.New(40, -1))
+		err = second.AddExecutedLiability(1, 2, "ETH", Execution{Amount: decimal.New(100, -2)})
+		assert.NoError(t, err)
+		second.AddPendingLiability(2, 0, "LTC", decimal.NewFromFloat(3))
+		second.AddPendingLiability(0, 1, "BTC", decimal.NewFromFloat(0.2))
+		second.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(1))
+
+		expected, err := first.EncodeToBytes()
+		assert.NoError(t, err)
+
+		for i := 0; i < 20; i++ {
+			encoded, err := second.EncodeToBytes()
+			assert.NoError(t, err)
+			assert.Equal(t, expected, encoded)
+		}
+	})
+
+	t.Run("encoding starts with version", func(t *testing.T) {
+		state := make(LiabilitiesState)
+		state.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(1))
+
+		encoded, err := state.EncodeToBytes()
+		assert.NoError(t, err)
+		assert.Equal(t, append(make([]byte, 31), EncodingVersion), encoded[:32])
+	})
+}
+
+func TestDecodeFromBytes(t *testing.T) {
+	t.Run("empty byte array", func(t *testing.T) {
+		_, err := DecodeFromBytes([]byte{})
+		assert.ErrorIs(t, err, ErrEmptyByteArray)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		state := make(LiabilitiesState)
+		encoded, err := state.EncodeToBytes()
+		assert.NoError(t, err)
+
+		encoded[31] = EncodingVersion + 1
+		_, err = DecodeFromBytes(encoded)
+		assert.ErrorIs(t, err, ErrUnsupportedVersion)
+	})
+
+	t.Run("decode executed and pending liabilities", func(t *testing.T) {
+		state := make(LiabilitiesState)
+		state.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(2.5))
+		state.AddPendingLiability(1, 0, "BTC", decimal.NewFromFloat(0.3))
+		err := state.AddExecutedLiability(1, 0, "BTC", Execution{Amount: decimal.NewFromFloat(0.1)})
+		assert.NoError(t, err)
+
+		encoded, err := state.EncodeToBytes()
+		assert.NoError(t, err)
+
+		decoded, err := DecodeFromBytes(encoded)
+		assert.NoError(t, err)
+		assert.True(t, decoded[0][1].PendingAmount("ETH").Equal(decimal.NewFromFloat(2.5)))
+		assert.True(t, decoded[1][0].PendingAmount("BTC").Equal(decimal.NewFromFloat(0.2)))
+		assert.True(t, decoded[1][0].ExecutedAmount("BTC").Equal(decimal.NewFromFloat(0.1)))
+	})
+
+	t.Run("decode execution history", func(t *testing.T) {
+		state := make(LiabilitiesState)
+		state.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(3))
+		state.AddPendingLiability(0, 2, "ETH", decimal.NewFromFloat(1))
+		executions := []Execution{
+			{Amount: decimal.NewFromFloat(2), TurnNum: 3, Reference: "order-2", Timestamp: 1650000000},
+			{Amount: decimal.NewFromFloat(0.5), TurnNum: 4, Reference: "order-1", Timestamp: 1640000000},
+		}
+		for _, execution := range executions {
+			err := state.AddExecutedLiability(0, 1, "ETH", execution)
+			assert.NoError(t, err)
+		}
+
+		encoded, err := state.EncodeToBytes()
+		assert.NoError(t, err)
+
+		decoded, err := DecodeFromBytes(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, executions, decoded[0][1].Executed["ETH"])
+	})
+
+	t.Run("decode pending metadata", func(t *testing.T) {
+		state := make(LiabilitiesState)
+		entries := []PendingEntry{
+			{Amount: decimal.NewFromFloat(2), Reference: "order-2", TurnNum: 3, Timestamp: 1650000000, Expiry: 1650000600},
+			{Amount: decimal.NewFromFloat(1), Reference: "order-1", TurnNum: 4, Timestamp: 1640000000},
+		}
+		for _, entry := range entries {
+			err := state.AddPendingEntry(0, 1, "ETH", entry)
+			assert.NoError(t, err)
+		}
+
+		encoded, err := state.EncodeToBytes()
+		assert.NoError(t, err)
+
+		decoded, err := DecodeFromBytes(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, entries, decoded[0][1].Pending["ETH"])
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		state := make(LiabilitiesState)
+		state.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(1))
+		encoded, err := state.EncodeToBytes()
+		assert.NoError(t, err)
+
+		_, err = DecodeFromBytes(append(encoded, make([]byte, 32)...))
+		assert.ErrorIs(t, err, ErrInvalidEncoding)
+	})
+
+	t.Run("non canonical encoding", func(t *testing.T) {
+		cases := map[string][]encodedPending{
+			"amount is not normalized": {
+				{From: bigUint(0), To: bigUint(1), Asset: "ETH", Coefficient: big.NewInt(40), Exponent: -1},
+			},
+			"entries are not sorted": {
+				{From: bigUint(1), To: bigUint(0), Asset: "ETH", Coefficient: big.NewInt(1)},
+				{From: bigUint(0), To: bigUint(1), Asset: "ETH", Coefficient: big.NewInt(1)},
+			},
+		}
+
+		for name, pending := range cases {
+			t.Run(name, func(t *testing.T) {
+				encoded, err := arguments.Pack(EncodingVersion, pending, []encodedExecution{})
+				assert.NoError(t, err)
+
+				_, err = DecodeFromBytes(encoded)
+				assert.ErrorIs(t, err, ErrInvalidEncoding)
+			})
+		}
+	})
+
+	t.Run("invalid entries", func(t *testing.T) {
+		overflow := new(big.Int).Lsh(big.NewInt(1), 64)
+		cases := []struct {
+			name    string
+			pending encodedPending
+			err     error
+		}{
+			{"index overflows", encodedPending{From: overflow, To: bigUint(1), Asset: "ETH", Coefficient: big.NewInt(1)}, ErrParticipantIndex},
+			{"index exceeds participants limit", encodedPending{From: bigUint(0), To: bigUint(MaxParticipants), Asset: "ETH", Coefficient: big.NewInt(1)}, ErrParticipantIndex},
+			{"negative amount", encodedPending{From: bigUint(0), To: bigUint(1), Asset: "ETH", Coefficient: big.NewInt(-1)}, ErrInvalidAmount},
+			{"zero amount", encodedPending{From: bigUint(0), To: bigUint(1), Asset: "ETH", Coefficient: big.NewInt(0)}, ErrInvalidAmount},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				encoded, err := arguments.Pack(EncodingVersion, []encodedPending{c.pending}, []encodedExecution{})
+				assert.NoError(t, err)
+
+				_, err = DecodeFromBytes(encoded)
+				assert.ErrorIs(t, err, c.err)
+			})
+		}
+	})
+
+	t.Run("decode legacy gob encoding", func(t *testing.T) {
+		legacyState := map[uint]map[uint]*gobLiabilities{
+			0: {1: {
+				Pending:  map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(0.3)},
+				Executed: map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(0.1)},
+			}},
+		}
+
+		buf := bytes.Buffer{}
+		err := gob.NewEncoder(&buf).Encode(legacyState)
+		assert.NoError(t, err)
+
+		decoded, err := DecodeFromBytes(buf.Bytes())
+		assert.NoError(t, err)
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(0.3)}, decoded[0][1].PendingAmounts())
+		assert.Equal(t, []Execution{{Amount: decimal.NewFromFloat(0.1)}}, decoded[0][1].Executed["ETH"])
+	})
+}
+
+func TestValidateParticipants(t *testing.T) {
+	state := make(LiabilitiesState)
+	state.AddPendingLiability(0, 2, "ETH", decimal.NewFromFloat(1))
+
+	assert.NoError(t, state.ValidateParticipants(3))
+	assert.ErrorIs(t, state.ValidateParticipants(2), ErrParticipantIndex)
+}
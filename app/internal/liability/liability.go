@@ -1,8 +1,6 @@
 package liability
 
 import (
-	"bytes"
-	"encoding/gob"
 	"errors"
 	"fmt"
 
@@ -14,14 +12,50 @@ var (
 	ErrNonExistingLiabilities = errors.New("liability: liabilities for such participant don't exist")
 	ErrNoPendingLiability     = errors.New("liability: liability with pending type doesn't exist")
 	ErrInvalidOperation       = errors.New("liability: given amount is bigger than actual amount")
+	ErrDuplicateReference     = errors.New("liability: liability with such reference already exists")
+	ErrUnknownReference       = errors.New("liability: liability with such reference doesn't exist")
+	ErrInvalidAmount          = errors.New("liability: amount must be positive")
 )
 
 type Asset string
 
-// Liabilities represents information about asset and amount of that asset
+// Kind represents liability kind.
+type Kind uint8
+
+const (
+	Pending Kind = iota
+	Executed
+)
+
+// Execution represents single execution of liability recorded in the ledger.
+type Execution struct {
+	Amount    decimal.Decimal
+	TurnNum   uint64
+	Reference string
+	Timestamp int64
+}
+
+// PendingEntry represents single pending liability with reference of the trade or order which created it.
+// Expiry is unix time after which liability is reverted, zero expiry means liability doesn't expire.
+type PendingEntry struct {
+	Amount    decimal.Decimal
+	Reference string
+	TurnNum   uint64
+	Timestamp int64
+	Expiry    int64
+}
+
+// Expired returns true if entry has expiry which has passed by the given unix time.
+func (e PendingEntry) Expired(now int64) bool {
+	return e.Expiry != 0 && e.Expiry <= now
+}
+
+// Liabilities represents information about asset and amount of that asset.
+// Pending liabilities are kept as list of entries per asset in order of creation.
+// Executed liabilities are kept as append-only list of executions per asset.
 type Liabilities struct {
-	Pending  map[Asset]decimal.Decimal
-	Executed map[Asset]decimal.Decimal
+	Pending  map[Asset][]PendingEntry
+	Executed map[Asset][]Execution
 }
 
 // LiabilitiesMap represents information about participant index and appropriate Liability
@@ -30,14 +64,14 @@ type LiabilitiesMap map[uint]*Liabilities
 // LiabilitiesState represents information about participant index and appropriate LiabilitiesMap
 // Example:
 // FROM: {
-// 	TO: { Pending: { ETH: 1, BTC: 0.1 }, Executed: { ETH: 7 } }
+// 	TO: { Pending: { ETH: [{ Amount: 1, Reference: "order-1" }] }, Executed: { ETH: [{ Amount: 3 }, { Amount: 4 }] } }
 // }
 type LiabilitiesState map[uint]LiabilitiesMap
 
 // NewLiabilities creates new Liabilities instance.
 func NewLiabilities() *Liabilities {
-	pending := make(map[Asset]decimal.Decimal)
-	executed := make(map[Asset]decimal.Decimal)
+	pending := make(map[Asset][]PendingEntry)
+	executed := make(map[Asset][]Execution)
 
 	return &Liabilities{
 		Pending:  pending,
@@ -47,48 +81,208 @@ func NewLiabilities() *Liabilities {
 
 // AddPendingLiability add pending liability.
 func (l *Liabilities) AddPendingLiability(asset Asset, amount decimal.Decimal) {
-	l.Pending[asset] = l.Pending[asset].Add(amount)
+	l.Pending[asset] = append(l.Pending[asset], PendingEntry{Amount: amount})
 }
 
-// AddExecutedLiability add executed liability.
-func (l *Liabilities) AddExecutedLiability(asset Asset, amount decimal.Decimal) error {
-	err := l.validate(asset, amount)
+// AddPendingEntry adds pending liability entry. Amount must be positive and reference must be unique if it is set.
+func (l *Liabilities) AddPendingEntry(asset Asset, entry PendingEntry) error {
+	if !entry.Amount.IsPositive() {
+		return ErrInvalidAmount
+	}
+
+	if entry.Reference != "" {
+		if _, _, found := l.find(entry.Reference); found {
+			return ErrDuplicateReference
+		}
+	}
+
+	l.Pending[asset] = append(l.Pending[asset], entry)
+
+	return nil
+}
+
+// AddExecutedLiability appends execution of pending liability to the executed ledger.
+// Pending entries are consumed in order of creation.
+func (l *Liabilities) AddExecutedLiability(asset Asset, execution Execution) error {
+	err := l.consume(asset, execution.Amount)
 	if err != nil {
 		return err
 	}
 
-	l.Executed[asset] = amount
-	if l.Pending[asset].Equal(amount) {
-		delete(l.Pending, asset)
+	l.Executed[asset] = append(l.Executed[asset], execution)
+
+	return nil
+}
+
+// ExecuteByReference appends execution of pending liability with the given reference to the executed ledger.
+// Zero execution amount executes the whole remaining amount of the liability, negative amount is rejected.
+func (l *Liabilities) ExecuteByReference(reference string, execution Execution) error {
+	if execution.Amount.IsNegative() {
+		return ErrInvalidAmount
+	}
+
+	asset, index, found := l.find(reference)
+	if !found {
+		return ErrUnknownReference
+	}
+
+	entry := l.Pending[asset][index]
+	if execution.Amount.IsZero() {
+		execution.Amount = entry.Amount
+	}
+	if execution.Amount.Cmp(entry.Amount) == 1 {
+		return ErrInvalidOperation
+	}
+
+	execution.Reference = reference
+	l.Executed[asset] = append(l.Executed[asset], execution)
+
+	if execution.Amount.Equal(entry.Amount) {
+		l.remove(asset, index)
 	} else {
-		l.Pending[asset] = l.Pending[asset].Sub(amount)
+		l.Pending[asset][index].Amount = entry.Amount.Sub(execution.Amount)
 	}
 
 	return nil
 }
 
+// PendingAmount returns total pending amount of the asset.
+func (l *Liabilities) PendingAmount(asset Asset) decimal.Decimal {
+	total := decimal.Zero
+	for _, entry := range l.Pending[asset] {
+		total = total.Add(entry.Amount)
+	}
+
+	return total
+}
+
+// PendingAmounts returns total pending amount per asset.
+func (l *Liabilities) PendingAmounts() map[Asset]decimal.Decimal {
+	amounts := make(map[Asset]decimal.Decimal)
+	for asset := range l.Pending {
+		amounts[asset] = l.PendingAmount(asset)
+	}
+
+	return amounts
+}
+
+// ExecutedAmount returns total executed amount of the asset.
+func (l *Liabilities) ExecutedAmount(asset Asset) decimal.Decimal {
+	total := decimal.Zero
+	for _, execution := range l.Executed[asset] {
+		total = total.Add(execution.Amount)
+	}
+
+	return total
+}
+
+// ExecutedAmounts returns total executed amount per asset.
+func (l *Liabilities) ExecutedAmounts() map[Asset]decimal.Decimal {
+	amounts := make(map[Asset]decimal.Decimal)
+	for asset := range l.Executed {
+		amounts[asset] = l.ExecutedAmount(asset)
+	}
+
+	return amounts
+}
+
 // AddRevertLiability reverts liability.
+// Pending entries are reverted in order of creation.
 func (l *Liabilities) AddRevertLiability(asset Asset, amount decimal.Decimal) error {
+	return l.consume(asset, amount)
+}
+
+// RevertByReference reverts the whole remaining amount of pending liability with the given reference.
+func (l *Liabilities) RevertByReference(reference string) error {
+	asset, index, found := l.find(reference)
+	if !found {
+		return ErrUnknownReference
+	}
+
+	l.remove(asset, index)
+
+	return nil
+}
+
+// RevertExpired reverts pending liabilities expired by the given unix time and returns number of reverted entries.
+func (l *Liabilities) RevertExpired(now int64) int {
+	reverted := 0
+	for asset, entries := range l.Pending {
+		var active []PendingEntry
+		for _, entry := range entries {
+			if entry.Expired(now) {
+				reverted++
+				continue
+			}
+			active = append(active, entry)
+		}
+
+		if len(active) == 0 {
+			delete(l.Pending, asset)
+		} else {
+			l.Pending[asset] = active
+		}
+	}
+
+	return reverted
+}
+
+// consume subtracts amount from pending entries of the asset in order of creation.
+func (l *Liabilities) consume(asset Asset, amount decimal.Decimal) error {
 	err := l.validate(asset, amount)
 	if err != nil {
 		return err
 	}
 
-	l.Pending[asset] = l.Pending[asset].Sub(amount)
-	if l.Pending[asset].Equal(decimal.Zero) {
-		delete(l.Pending, asset)
+	for amount.IsPositive() {
+		entry := l.Pending[asset][0]
+		if entry.Amount.GreaterThan(amount) {
+			l.Pending[asset][0].Amount = entry.Amount.Sub(amount)
+			break
+		}
+
+		amount = amount.Sub(entry.Amount)
+		l.remove(asset, 0)
 	}
 
 	return nil
 }
 
+// find returns asset and index of pending entry with the given reference.
+func (l *Liabilities) find(reference string) (Asset, int, bool) {
+	for asset, entries := range l.Pending {
+		for index, entry := range entries {
+			if entry.Reference == reference {
+				return asset, index, true
+			}
+		}
+	}
+
+	return "", 0, false
+}
+
+// remove removes pending entry, asset without entries is removed as well.
+func (l *Liabilities) remove(asset Asset, index int) {
+	entries := append(l.Pending[asset][:index:index], l.Pending[asset][index+1:]...)
+	if len(entries) == 0 {
+		delete(l.Pending, asset)
+		return
+	}
+
+	l.Pending[asset] = entries
+}
+
 // validate validates given params for further operations.
 func (l *Liabilities) validate(asset Asset, amount decimal.Decimal) error {
+	if !amount.IsPositive() {
+		return ErrInvalidAmount
+	}
+
 	if _, ok := l.Pending[asset]; !ok {
 		return ErrNoPendingLiability
 	}
 
-	if amount.Cmp(l.Pending[asset]) == 1 {
+	if amount.Cmp(l.PendingAmount(asset)) == 1 {
 		return ErrInvalidOperation
 	}
 
@@ -111,13 +305,13 @@ func (ls LiabilitiesState) AddPendingLiability(from, to uint, asset Asset, amoun
 }
 
 // AddExecutedLiability adds new executed liability to liabilities state.
-func (ls LiabilitiesState) AddExecutedLiability(from, to uint, asset Asset, amount decimal.Decimal) error {
+func (ls LiabilitiesState) AddExecutedLiability(from, to uint, asset Asset, execution Execution) error {
 	_, found := ls[from][to]
 	if !found {
 		return ErrNonExistingLiabilities
 	}
 
-	err := ls[from][to].AddExecutedLiability(asset, amount)
+	err := ls[from][to].AddExecutedLiability(asset, execution)
 	if err != nil {
 		return err
 	}
@@ -140,42 +334,73 @@ func (ls LiabilitiesState) AddRevertLiability(from, to uint, asset Asset, amount
 	return nil
 }
 
-// Print prints pretty LiabilitiesState.
-func (ls LiabilitiesState) Print() {
-	for index, liabilitiesMap := range ls {
-		fmt.Printf("From Participant [%d] ", index)
-		for pIndex, liabilities := range liabilitiesMap {
-			fmt.Printf("To Participant [%d] %+v\n", pIndex, liabilities)
+// AddPendingEntry adds new pending liability entry to liabilities state.
+// Amount must be positive and reference must be unique within liabilities state if it is set.
+func (ls LiabilitiesState) AddPendingEntry(from, to uint, asset Asset, entry PendingEntry) error {
+	if !entry.Amount.IsPositive() {
+		return ErrInvalidAmount
+	}
+
+	if entry.Reference != "" {
+		if _, found := ls.find(entry.Reference); found {
+			return ErrDuplicateReference
 		}
 	}
+
+	return ls.liabilities(from, to).AddPendingEntry(asset, entry)
 }
 
-// EncodeToBytes tranform liabilitiesState struct to bytes.
-func (ls LiabilitiesState) EncodeToBytes() ([]byte, error) {
-	buf := bytes.Buffer{}
-	enc := gob.NewEncoder(&buf)
+// ExecuteByReference executes pending liability with the given reference.
+func (ls LiabilitiesState) ExecuteByReference(reference string, execution Execution) error {
+	liabilities, found := ls.find(reference)
+	if !found {
+		return ErrUnknownReference
+	}
 
-	err := enc.Encode(ls)
-	if err != nil {
-		return []byte{}, err
+	return liabilities.ExecuteByReference(reference, execution)
+}
+
+// RevertByReference reverts pending liability with the given reference.
+func (ls LiabilitiesState) RevertByReference(reference string) error {
+	liabilities, found := ls.find(reference)
+	if !found {
+		return ErrUnknownReference
 	}
 
-	return buf.Bytes(), nil
+	return liabilities.RevertByReference(reference)
 }
 
-// DecodeFromBytes tranform bytes to liabilitiesState struct.
-func DecodeFromBytes(liabilityData []byte) (LiabilitiesState, error) {
-	if len(liabilityData) == 0 {
-		return LiabilitiesState{}, ErrEmptyByteArray
+// RevertExpired reverts pending liabilities expired by the given unix time and returns number of reverted entries.
+func (ls LiabilitiesState) RevertExpired(now int64) int {
+	reverted := 0
+	for _, liabilitiesMap := range ls {
+		for _, liabilities := range liabilitiesMap {
+			reverted += liabilities.RevertExpired(now)
+		}
 	}
 
-	buf := bytes.NewBuffer(liabilityData)
-	dec := gob.NewDecoder(buf)
-	var liabilitiesState LiabilitiesState
+	return reverted
+}
 
-	if err := dec.Decode(&liabilitiesState); err != nil {
-		return LiabilitiesState{}, err
+// find returns liabilities which contain pending entry with the given reference.
+func (ls LiabilitiesState) find(reference string) (*Liabilities, bool) {
+	for _, liabilitiesMap := range ls {
+		for _, liabilities := range liabilitiesMap {
+			if _, _, found := liabilities.find(reference); found {
+				return liabilities, true
+			}
+		}
 	}
 
-	return liabilitiesState, nil
+	return nil, false
+}
+
+// Print prints pretty LiabilitiesState.
+func (ls LiabilitiesState) Print() {
+	for index, liabilitiesMap := range ls {
+		fmt.Printf("From Participant [%d] ", index)
+		for pIndex, liabilities := range liabilitiesMap {
+			fmt.Printf("To Participant [%d] %+v\n", pIndex, liabilities)
+		}
+	}
 }
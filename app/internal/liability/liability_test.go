@@ -18,19 +18,19 @@ func TestLiabilities(t *testing.T) {
 		assert.NotEmpty(t, liabilities)
 
 		liabilities.AddPendingLiability("ETH", decimal.NewFromFloat(1))
-		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(1)}, liabilities.Pending)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.Executed)
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(1)}, liabilities.PendingAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.ExecutedAmounts())
 
 		liabilities.AddPendingLiability("ETH", decimal.NewFromFloat(2))
-		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(3)}, liabilities.Pending)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.Executed)
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(3)}, liabilities.PendingAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.ExecutedAmounts())
 
 		liabilities.AddPendingLiability("BTC", decimal.NewFromFloat(12))
 		assert.Equal(t, map[Asset]decimal.Decimal{
 			"ETH": decimal.NewFromFloat(3),
 			"BTC": decimal.NewFromFloat(12),
-		}, liabilities.Pending)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.Executed)
+		}, liabilities.PendingAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.ExecutedAmounts())
 	})
 
 	t.Run("acknowledge liability", func(t *testing.T) {
@@ -38,26 +38,34 @@ func TestLiabilities(t *testing.T) {
 		assert.NotEmpty(t, liabilities)
 
 		// Execute, no pending asset existing
-		err := liabilities.AddExecutedLiability("ETH", decimal.NewFromFloat(1))
+		err := liabilities.AddExecutedLiability("ETH", Execution{Amount: decimal.NewFromFloat(1)})
 		assert.Error(t, err, ErrNoPendingLiability)
 
 		// Execute, same amount
 		liabilities.AddPendingLiability("ETH", decimal.NewFromFloat(2))
-		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, liabilities.Pending)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.Executed)
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, liabilities.PendingAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.ExecutedAmounts())
 
-		err = liabilities.AddExecutedLiability("ETH", decimal.NewFromFloat(2))
+		err = liabilities.AddExecutedLiability("ETH", Execution{Amount: decimal.NewFromFloat(2)})
 		assert.NoError(t, err)
-		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, liabilities.Executed)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.Pending)
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, liabilities.ExecutedAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.PendingAmounts())
 
 		// Execute, bigger amount
 		liabilities.AddPendingLiability("BTC", decimal.NewFromFloat(22))
-		assert.Equal(t, map[Asset]decimal.Decimal{"BTC": decimal.NewFromFloat(22)}, liabilities.Pending)
-		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, liabilities.Executed)
+		assert.Equal(t, map[Asset]decimal.Decimal{"BTC": decimal.NewFromFloat(22)}, liabilities.PendingAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, liabilities.ExecutedAmounts())
 
-		err = liabilities.AddExecutedLiability("BTC", decimal.NewFromFloat(23))
+		err = liabilities.AddExecutedLiability("BTC", Execution{Amount: decimal.NewFromFloat(23)})
 		assert.Error(t, err, ErrInvalidOperation)
+
+		// Execute, non positive amount
+		for _, amount := range []float64{0, -1} {
+			err = liabilities.AddExecutedLiability("BTC", Execution{Amount: decimal.NewFromFloat(amount)})
+			assert.ErrorIs(t, err, ErrInvalidAmount)
+		}
+		assert.Equal(t, map[Asset]decimal.Decimal{"BTC": decimal.NewFromFloat(22)}, liabilities.PendingAmounts())
+		assert.Empty(t, liabilities.Executed["BTC"])
 	})
 
 	t.Run("revert liability", func(t *testing.T) {
@@ -65,8 +73,8 @@ func TestLiabilities(t *testing.T) {
 		assert.NotEmpty(t, liabilities)
 
 		liabilities.AddPendingLiability("ETH", decimal.NewFromFloat(1))
-		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(1)}, liabilities.Pending)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.Executed)
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(1)}, liabilities.PendingAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.ExecutedAmounts())
 
 		// Revert non existing liability
 		err := liabilities.AddRevertLiability("BTC", decimal.NewFromFloat(1))
@@ -77,60 +85,78 @@ func TestLiabilities(t *testing.T) {
 		assert.Error(t, err, ErrInvalidOperation)
 
 		// Revert liability which was executed
-		err = liabilities.AddExecutedLiability("ETH", decimal.NewFromFloat(1))
+		err = liabilities.AddExecutedLiability("ETH", Execution{Amount: decimal.NewFromFloat(1)})
 		assert.NoError(t, err)
 		err = liabilities.AddRevertLiability("ETH", decimal.NewFromFloat(1))
 		assert.Error(t, err, ErrInvalidOperation)
 
 		// Successfull revert of liability
 		liabilities.AddPendingLiability("BTC", decimal.NewFromFloat(1))
-		assert.Equal(t, map[Asset]decimal.Decimal{"BTC": decimal.NewFromFloat(1)}, liabilities.Pending)
-		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(1)}, liabilities.Executed)
+		assert.Equal(t, map[Asset]decimal.Decimal{"BTC": decimal.NewFromFloat(1)}, liabilities.PendingAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(1)}, liabilities.ExecutedAmounts())
 
 		err = liabilities.AddRevertLiability("BTC", decimal.NewFromFloat(1))
 		assert.NoError(t, err)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.Pending)
-		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(1)}, liabilities.Executed)
+		assert.Equal(t, map[Asset]decimal.Decimal{}, liabilities.PendingAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(1)}, liabilities.ExecutedAmounts())
 	})
 }
 
+func TestExecutedLedger(t *testing.T) {
+	liabilities := NewLiabilities()
+	liabilities.AddPendingLiability("ETH", decimal.NewFromFloat(5))
+
+	first := Execution{Amount: decimal.NewFromFloat(2), TurnNum: 2, Reference: "fill-1", Timestamp: 1640000000}
+	second := Execution{Amount: decimal.NewFromFloat(1.5), TurnNum: 3, Reference: "fill-2", Timestamp: 1640000060}
+
+	err := liabilities.AddExecutedLiability("ETH", first)
+	assert.NoError(t, err)
+	err = liabilities.AddExecutedLiability("ETH", second)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Execution{first, second}, liabilities.Executed["ETH"])
+	assert.True(t, liabilities.ExecutedAmount("ETH").Equal(decimal.NewFromFloat(3.5)))
+	assert.True(t, liabilities.PendingAmount("ETH").Equal(decimal.NewFromFloat(1.5)))
+	assert.True(t, liabilities.ExecutedAmount("BTC").IsZero())
+}
+
 func TestLiabilitiesState(t *testing.T) {
 	t.Run("adds request liabilities", func(t *testing.T) {
 		state := make(LiabilitiesState)
 
 		state.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(2))
-		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, state[0][1].Pending)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].Executed)
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, state[0][1].PendingAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].ExecutedAmounts())
 
 		state.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(3))
-		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(5)}, state[0][1].Pending)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].Executed)
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(5)}, state[0][1].PendingAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].ExecutedAmounts())
 
 		state.AddPendingLiability(0, 1, "BTC", decimal.NewFromFloat(0.3))
 		assert.Equal(t, map[Asset]decimal.Decimal{
 			"ETH": decimal.NewFromFloat(5),
 			"BTC": decimal.NewFromFloat(0.3)},
-			state[0][1].Pending)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].Executed)
+			state[0][1].PendingAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].ExecutedAmounts())
 	})
 
 	t.Run("adds acknowledge liability", func(t *testing.T) {
 		state := make(LiabilitiesState)
 
 		// there is no to/from field
-		err := state.AddExecutedLiability(0, 1, "ETH", decimal.NewFromFloat(2))
+		err := state.AddExecutedLiability(0, 1, "ETH", Execution{Amount: decimal.NewFromFloat(2)})
 		assert.Error(t, err, ErrNonExistingLiabilities)
 
 		state.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(2))
 
 		// there is no to field
-		err = state.AddExecutedLiability(0, 4, "ETH", decimal.NewFromFloat(2))
+		err = state.AddExecutedLiability(0, 4, "ETH", Execution{Amount: decimal.NewFromFloat(2)})
 		assert.Error(t, err, ErrNonExistingLiabilities)
 
-		err = state.AddExecutedLiability(0, 1, "ETH", decimal.NewFromFloat(2))
+		err = state.AddExecutedLiability(0, 1, "ETH", Execution{Amount: decimal.NewFromFloat(2)})
 		assert.NoError(t, err)
-		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, state[0][1].Executed)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].Pending)
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, state[0][1].ExecutedAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].PendingAmounts())
 	})
 
 	t.Run("adds revert liability", func(t *testing.T) {
@@ -148,8 +174,8 @@ func TestLiabilitiesState(t *testing.T) {
 
 		err = state.AddRevertLiability(0, 1, "ETH", decimal.NewFromFloat(2))
 		assert.NoError(t, err)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].Executed)
-		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].Pending)
+		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].ExecutedAmounts())
+		assert.Equal(t, map[Asset]decimal.Decimal{}, state[0][1].PendingAmounts())
 	})
 
 	t.Run("encode to bytes", func(t *testing.T) {
@@ -174,3 +200,90 @@ func TestLiabilitiesState(t *testing.T) {
 		assert.Equal(t, state, decodedState)
 	})
 }
+
+func TestReferencedLiabilities(t *testing.T) {
+	getState := func(t *testing.T) LiabilitiesState {
+		state := make(LiabilitiesState)
+		entries := []PendingEntry{
+			{Amount: decimal.NewFromFloat(2), Reference: "order-1", TurnNum: 3, Timestamp: 1640000000},
+			{Amount: decimal.NewFromFloat(1), Reference: "order-2", TurnNum: 3, Timestamp: 1640000000, Expiry: 1640000100},
+		}
+		for _, entry := range entries {
+			err := state.AddPendingEntry(0, 1, "ETH", entry)
+			assert.NoError(t, err)
+		}
+
+		return state
+	}
+
+	t.Run("duplicate reference", func(t *testing.T) {
+		state := getState(t)
+		err := state.AddPendingEntry(1, 0, "BTC", PendingEntry{Amount: decimal.NewFromFloat(1), Reference: "order-1"})
+		assert.ErrorIs(t, err, ErrDuplicateReference)
+	})
+
+	t.Run("non positive amount", func(t *testing.T) {
+		state := getState(t)
+		for _, amount := range []float64{0, -1} {
+			err := state.AddPendingEntry(1, 0, "BTC", PendingEntry{Amount: decimal.NewFromFloat(amount), Reference: "order-3"})
+			assert.ErrorIs(t, err, ErrInvalidAmount)
+		}
+		assert.NotContains(t, state, uint(1))
+	})
+
+	t.Run("execute by reference", func(t *testing.T) {
+		state := getState(t)
+
+		err := state.ExecuteByReference("order-3", Execution{Amount: decimal.NewFromFloat(1)})
+		assert.ErrorIs(t, err, ErrUnknownReference)
+
+		err = state.ExecuteByReference("order-2", Execution{Amount: decimal.NewFromFloat(2)})
+		assert.ErrorIs(t, err, ErrInvalidOperation)
+
+		err = state.ExecuteByReference("order-2", Execution{Amount: decimal.NewFromFloat(-1)})
+		assert.ErrorIs(t, err, ErrInvalidAmount)
+		assert.True(t, state[0][1].PendingAmount("ETH").Equal(decimal.NewFromFloat(3)))
+		assert.Empty(t, state[0][1].Executed["ETH"])
+
+		err = state.ExecuteByReference("order-2", Execution{Amount: decimal.NewFromFloat(0.5), TurnNum: 4})
+		assert.NoError(t, err)
+		assert.Equal(t, "order-2", state[0][1].Executed["ETH"][0].Reference)
+		assert.True(t, state[0][1].Pending["ETH"][1].Amount.Equal(decimal.NewFromFloat(0.5)))
+
+		// zero amount executes the rest of liability
+		err = state.ExecuteByReference("order-1", Execution{TurnNum: 4})
+		assert.NoError(t, err)
+		assert.True(t, state[0][1].ExecutedAmount("ETH").Equal(decimal.NewFromFloat(2.5)))
+		assert.Equal(t, 1, len(state[0][1].Pending["ETH"]))
+		assert.Equal(t, "order-2", state[0][1].Pending["ETH"][0].Reference)
+	})
+
+	t.Run("revert by reference", func(t *testing.T) {
+		state := getState(t)
+
+		err := state.RevertByReference("order-1")
+		assert.NoError(t, err)
+		assert.True(t, state[0][1].PendingAmount("ETH").Equal(decimal.NewFromFloat(1)))
+
+		err = state.RevertByReference("order-1")
+		assert.ErrorIs(t, err, ErrUnknownReference)
+	})
+
+	t.Run("amount operations consume entries in order of creation", func(t *testing.T) {
+		state := getState(t)
+
+		err := state.AddExecutedLiability(0, 1, "ETH", Execution{Amount: decimal.NewFromFloat(2.5)})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(state[0][1].Pending["ETH"]))
+		assert.Equal(t, "order-2", state[0][1].Pending["ETH"][0].Reference)
+		assert.True(t, state[0][1].PendingAmount("ETH").Equal(decimal.NewFromFloat(0.5)))
+	})
+
+	t.Run("revert expired", func(t *testing.T) {
+		state := getState(t)
+
+		assert.Equal(t, 0, state.RevertExpired(1640000099))
+		assert.Equal(t, 1, state.RevertExpired(1640000100))
+		assert.Equal(t, map[Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, state[0][1].PendingAmounts())
+	})
+}
@@ -0,0 +1,178 @@
+package liability
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// NettingReference is the reference of executions produced by netting.
+const NettingReference = "netting"
+
+// Obligation represents amount of asset owed by one participant to another.
+type Obligation struct {
+	From   uint
+	To     uint
+	Asset  Asset
+	Amount decimal.Decimal
+}
+
+// Obligations returns liabilities of the given kind as obligations ordered by asset and participants.
+func (ls LiabilitiesState) Obligations(kind Kind) []Obligation {
+	var obligations []Obligation
+
+	for from, liabilitiesMap := range ls {
+		for to, liabilities := range liabilitiesMap {
+			amounts := liabilities.PendingAmounts()
+			if kind == Executed {
+				amounts = liabilities.ExecutedAmounts()
+			}
+
+			for asset, amount := range amounts {
+				obligations = append(obligations, Obligation{From: from, To: to, Asset: asset, Amount: amount})
+			}
+		}
+	}
+
+	sortObligations(obligations)
+
+	return obligations
+}
+
+// NetPositions returns net position of every participant per asset.
+// Position is positive for net creditor and negative for net debtor.
+func NetPositions(obligations []Obligation) map[Asset]map[uint]decimal.Decimal {
+	positions := make(map[Asset]map[uint]decimal.Decimal)
+
+	for _, o := range obligations {
+		if _, found := positions[o.Asset]; !found {
+			positions[o.Asset] = make(map[uint]decimal.Decimal)
+		}
+
+		positions[o.Asset][o.From] = positions[o.Asset][o.From].Sub(o.Amount)
+		positions[o.Asset][o.To] = positions[o.Asset][o.To].Add(o.Amount)
+	}
+
+	return positions
+}
+
+// BilateralNet offsets obligations between every pair of participants per asset,
+// so that at most one obligation per pair and asset is left.
+func BilateralNet(obligations []Obligation) []Obligation {
+	type pair struct {
+		low, high uint
+		asset     Asset
+	}
+
+	// positive balance means low participant owes high participant
+	balances := make(map[pair]decimal.Decimal)
+	for _, o := range obligations {
+		if o.From == o.To {
+			continue
+		}
+
+		if o.From < o.To {
+			key := pair{o.From, o.To, o.Asset}
+			balances[key] = balances[key].Add(o.Amount)
+		} else {
+			key := pair{o.To, o.From, o.Asset}
+			balances[key] = balances[key].Sub(o.Amount)
+		}
+	}
+
+	var netted []Obligation
+	for key, balance := range balances {
+		switch balance.Sign() {
+		case 1:
+			netted = append(netted, Obligation{From: key.low, To: key.high, Asset: key.asset, Amount: balance})
+		case -1:
+			netted = append(netted, Obligation{From: key.high, To: key.low, Asset: key.asset, Amount: balance.Neg()})
+		}
+	}
+
+	sortObligations(netted)
+
+	return netted
+}
+
+// MultilateralNet returns set of transfers which settles net positions of all participants.
+// For every asset the largest debtor is matched with the largest creditor, which results
+// in at most N-1 transfers for N participants with non-zero position.
+func MultilateralNet(obligations []Obligation) []Obligation {
+	type position struct {
+		index  uint
+		amount decimal.Decimal
+	}
+
+	var netted []Obligation
+	for asset, positions := range NetPositions(obligations) {
+		var debtors, creditors []position
+		for index, amount := range positions {
+			switch amount.Sign() {
+			case 1:
+				creditors = append(creditors, position{index, amount})
+			case -1:
+				debtors = append(debtors, position{index, amount.Neg()})
+			}
+		}
+
+		for _, list := range [][]position{debtors, creditors} {
+			sort.Slice(list, func(i, j int) bool {
+				if c := list[i].amount.Cmp(list[j].amount); c != 0 {
+					return c > 0
+				}
+				return list[i].index < list[j].index
+			})
+		}
+
+		for d, c := 0, 0; d < len(debtors) && c < len(creditors); {
+			amount := decimal.Min(debtors[d].amount, creditors[c].amount)
+			netted = append(netted, Obligation{From: debtors[d].index, To: creditors[c].index, Asset: asset, Amount: amount})
+
+			debtors[d].amount = debtors[d].amount.Sub(amount)
+			creditors[c].amount = creditors[c].amount.Sub(amount)
+			if debtors[d].amount.IsZero() {
+				d++
+			}
+			if creditors[c].amount.IsZero() {
+				c++
+			}
+		}
+	}
+
+	sortObligations(netted)
+
+	return netted
+}
+
+// Net returns a new liabilities state where pending and executed liabilities are replaced
+// with their multilateral netted obligations. Pending entries lose their references and
+// expiries, executed ledger is collapsed to a single execution per obligation.
+func (ls LiabilitiesState) Net() LiabilitiesState {
+	netted := make(LiabilitiesState)
+
+	for _, o := range MultilateralNet(ls.Obligations(Pending)) {
+		netted.AddPendingLiability(o.From, o.To, o.Asset, o.Amount)
+	}
+
+	for _, o := range MultilateralNet(ls.Obligations(Executed)) {
+		netted.liabilities(o.From, o.To).Executed[o.Asset] = []Execution{{Amount: o.Amount, Reference: NettingReference}}
+	}
+
+	return netted
+}
+
+// sortObligations orders obligations by asset, debtor and creditor.
+func sortObligations(obligations []Obligation) {
+	sort.Slice(obligations, func(i, j int) bool {
+		a, b := obligations[i], obligations[j]
+		if a.Asset != b.Asset {
+			return a.Asset < b.Asset
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+
+		return a.To < b.To
+	})
+}
@@ -0,0 +1,112 @@
+package liability
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func getCycleState() LiabilitiesState {
+	state := make(LiabilitiesState)
+	state.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(10))
+	state.AddPendingLiability(1, 2, "ETH", decimal.NewFromFloat(10))
+	state.AddPendingLiability(2, 0, "ETH", decimal.NewFromFloat(10))
+	state.AddPendingLiability(1, 0, "BTC", decimal.NewFromFloat(2))
+	state.AddPendingLiability(0, 1, "BTC", decimal.NewFromFloat(0.5))
+
+	return state
+}
+
+func assertObligations(t *testing.T, expected, actual []Obligation) {
+	assert.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		assert.Equal(t, expected[i].From, actual[i].From)
+		assert.Equal(t, expected[i].To, actual[i].To)
+		assert.Equal(t, expected[i].Asset, actual[i].Asset)
+		assert.True(t, expected[i].Amount.Equal(actual[i].Amount), "%s != %s", expected[i].Amount, actual[i].Amount)
+	}
+}
+
+func TestObligations(t *testing.T) {
+	state := getCycleState()
+	err := state.AddExecutedLiability(1, 0, "BTC", Execution{Amount: decimal.NewFromFloat(1)})
+	assert.NoError(t, err)
+
+	assertObligations(t, []Obligation{
+		{From: 0, To: 1, Asset: "BTC", Amount: decimal.NewFromFloat(0.5)},
+		{From: 1, To: 0, Asset: "BTC", Amount: decimal.NewFromFloat(1)},
+		{From: 0, To: 1, Asset: "ETH", Amount: decimal.NewFromFloat(10)},
+		{From: 1, To: 2, Asset: "ETH", Amount: decimal.NewFromFloat(10)},
+		{From: 2, To: 0, Asset: "ETH", Amount: decimal.NewFromFloat(10)},
+	}, state.Obligations(Pending))
+
+	assertObligations(t, []Obligation{
+		{From: 1, To: 0, Asset: "BTC", Amount: decimal.NewFromFloat(1)},
+	}, state.Obligations(Executed))
+}
+
+func TestNetPositions(t *testing.T) {
+	positions := NetPositions(getCycleState().Obligations(Pending))
+
+	for _, position := range positions["ETH"] {
+		assert.True(t, position.IsZero())
+	}
+	assert.True(t, positions["BTC"][0].Equal(decimal.NewFromFloat(1.5)))
+	assert.True(t, positions["BTC"][1].Equal(decimal.NewFromFloat(-1.5)))
+}
+
+func TestBilateralNet(t *testing.T) {
+	netted := BilateralNet(getCycleState().Obligations(Pending))
+
+	assertObligations(t, []Obligation{
+		{From: 1, To: 0, Asset: "BTC", Amount: decimal.NewFromFloat(1.5)},
+		{From: 0, To: 1, Asset: "ETH", Amount: decimal.NewFromFloat(10)},
+		{From: 1, To: 2, Asset: "ETH", Amount: decimal.NewFromFloat(10)},
+		{From: 2, To: 0, Asset: "ETH", Amount: decimal.NewFromFloat(10)},
+	}, netted)
+}
+
+func TestMultilateralNet(t *testing.T) {
+	t.Run("cycle is fully offset", func(t *testing.T) {
+		netted := MultilateralNet(getCycleState().Obligations(Pending))
+
+		assertObligations(t, []Obligation{
+			{From: 1, To: 0, Asset: "BTC", Amount: decimal.NewFromFloat(1.5)},
+		}, netted)
+	})
+
+	t.Run("at most N-1 transfers", func(t *testing.T) {
+		obligations := []Obligation{
+			{From: 0, To: 1, Asset: "ETH", Amount: decimal.NewFromFloat(5)},
+			{From: 0, To: 2, Asset: "ETH", Amount: decimal.NewFromFloat(3)},
+			{From: 1, To: 2, Asset: "ETH", Amount: decimal.NewFromFloat(4)},
+			{From: 3, To: 0, Asset: "ETH", Amount: decimal.NewFromFloat(1)},
+			{From: 3, To: 1, Asset: "ETH", Amount: decimal.NewFromFloat(2)},
+		}
+
+		netted := MultilateralNet(obligations)
+		assert.LessOrEqual(t, len(netted), 3)
+
+		expected := NetPositions(obligations)
+		actual := NetPositions(netted)
+		for index, position := range expected["ETH"] {
+			assert.True(t, position.Equal(actual["ETH"][index]))
+		}
+	})
+}
+
+func TestNet(t *testing.T) {
+	state := getCycleState()
+	state.AddPendingLiability(2, 1, "LTC", decimal.NewFromFloat(4))
+	err := state.AddExecutedLiability(2, 1, "LTC", Execution{Amount: decimal.NewFromFloat(3)})
+	assert.NoError(t, err)
+
+	netted := state.Net()
+
+	assert.Equal(t, map[Asset]decimal.Decimal{"BTC": decimal.NewFromFloat(1.5)}, netted[1][0].PendingAmounts())
+	assert.Equal(t, map[Asset]decimal.Decimal{}, netted[1][0].ExecutedAmounts())
+	assert.True(t, netted[2][1].PendingAmount("LTC").Equal(decimal.NewFromFloat(1)))
+	assert.True(t, netted[2][1].ExecutedAmount("LTC").Equal(decimal.NewFromFloat(3)))
+	assert.Equal(t, 2, len(netted))
+}
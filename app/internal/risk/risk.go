@@ -0,0 +1,212 @@
+package risk
+
+import (
+	"app/internal/liability"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInvalidRatio  = errors.New("risk: invalid collateral ratio")
+	ErrUnknownPrice  = errors.New("risk: asset has no price")
+	ErrExposureLimit = errors.New("risk: exposure exceeds collateral limit")
+)
+
+// Prices maps asset to its price in a common quote currency.
+type Prices map[liability.Asset]decimal.Decimal
+
+// Collateral stores amount of every asset locked by participant.
+// Example:
+// PARTICIPANT: { ETH: 1, BTC: 0.1 }
+type Collateral map[uint]map[liability.Asset]decimal.Decimal
+
+// Exposure stores net amount of every asset owed by participant.
+// Only participants and assets with positive net debt are present.
+type Exposure map[uint]map[liability.Asset]decimal.Decimal
+
+// Assessment represents participant exposure compared with participant collateral.
+// Asset is empty when exposure is valued in the quote currency.
+type Assessment struct {
+	Participant uint
+	Asset       liability.Asset
+	Exposure    decimal.Decimal
+	Collateral  decimal.Decimal
+	// Breach is set when exposure exceeds maximum collateral ratio.
+	Breach bool
+	// TopUp is set when exposure exceeds top up or maximum ratio and participant should lock more collateral.
+	TopUp bool
+}
+
+// Policy stores collateral requirements applied to liabilities.
+type Policy struct {
+	// MaxRatio is the maximum allowed ratio of exposure to collateral.
+	MaxRatio decimal.Decimal
+	// TopUpRatio is the ratio of exposure to collateral after which participant is flagged to top up.
+	TopUpRatio decimal.Decimal
+	// Prices is optional, when set exposure and collateral of all assets are valued in the quote currency.
+	Prices Prices
+}
+
+// NewPolicy returns a new Policy from supplied params.
+// Top up ratio must not exceed maximum ratio, with zero top up ratio only participants breaching
+// maximum ratio are flagged to top up.
+func NewPolicy(maxRatio, topUpRatio decimal.Decimal, prices Prices) (*Policy, error) {
+	if !maxRatio.IsPositive() || topUpRatio.IsNegative() || topUpRatio.GreaterThan(maxRatio) {
+		return nil, ErrInvalidRatio
+	}
+
+	return &Policy{
+		MaxRatio:   maxRatio,
+		TopUpRatio: topUpRatio,
+		Prices:     prices,
+	}, nil
+}
+
+// Exposures returns net exposure of every participant per asset.
+// Both pending and executed liabilities are counted since neither is settled yet.
+func Exposures(ls liability.LiabilitiesState) Exposure {
+	obligations := append(ls.Obligations(liability.Pending), ls.Obligations(liability.Executed)...)
+
+	exposure := make(Exposure)
+	for asset, positions := range liability.NetPositions(obligations) {
+		for index, position := range positions {
+			if !position.IsNegative() {
+				continue
+			}
+
+			if _, found := exposure[index]; !found {
+				exposure[index] = make(map[liability.Asset]decimal.Decimal)
+			}
+			exposure[index][asset] = position.Neg()
+		}
+	}
+
+	return exposure
+}
+
+// Assess compares exposure of every participant with the collateral.
+// Assessments are ordered by participant and asset.
+func (p *Policy) Assess(ls liability.LiabilitiesState, collateral Collateral) ([]Assessment, error) {
+	var assessments []Assessment
+	if p.Prices != nil {
+		valued, err := p.value(ls, collateral)
+		if err != nil {
+			return nil, err
+		}
+		assessments = valued
+	} else {
+		for index, assets := range Exposures(ls) {
+			for asset, amount := range assets {
+				assessments = append(assessments, p.assessment(index, asset, amount, collateral[index][asset]))
+			}
+		}
+	}
+
+	sort.Slice(assessments, func(i, j int) bool {
+		if assessments[i].Participant != assessments[j].Participant {
+			return assessments[i].Participant < assessments[j].Participant
+		}
+
+		return assessments[i].Asset < assessments[j].Asset
+	})
+
+	return assessments, nil
+}
+
+// Check returns an error if exposure of any participant exceeds maximum collateral ratio.
+func (p *Policy) Check(ls liability.LiabilitiesState, collateral Collateral) error {
+	assessments, err := p.Assess(ls, collateral)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range assessments {
+		if a.Breach {
+			return fmt.Errorf("%w: participant %d exposure %s, collateral %s", ErrExposureLimit, a.Participant, a.Exposure, a.Collateral)
+		}
+	}
+
+	return nil
+}
+
+// TopUps returns indexes of participants who need to lock more collateral.
+func (p *Policy) TopUps(ls liability.LiabilitiesState, collateral Collateral) ([]uint, error) {
+	assessments, err := p.Assess(ls, collateral)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []uint
+	for _, a := range assessments {
+		if a.TopUp && (len(indexes) == 0 || indexes[len(indexes)-1] != a.Participant) {
+			indexes = append(indexes, a.Participant)
+		}
+	}
+
+	return indexes, nil
+}
+
+// value returns assessment of every participant valued in the quote currency.
+// Positions in different assets offset each other.
+func (p *Policy) value(ls liability.LiabilitiesState, collateral Collateral) ([]Assessment, error) {
+	obligations := append(ls.Obligations(liability.Pending), ls.Obligations(liability.Executed)...)
+
+	balances := make(map[uint]decimal.Decimal)
+	for asset, positions := range liability.NetPositions(obligations) {
+		price, err := p.price(asset)
+		if err != nil {
+			return nil, err
+		}
+
+		for index, position := range positions {
+			balances[index] = balances[index].Add(position.Mul(price))
+		}
+	}
+
+	var assessments []Assessment
+	for index, balance := range balances {
+		if !balance.IsNegative() {
+			continue
+		}
+
+		total := decimal.Zero
+		for asset, amount := range collateral[index] {
+			price, err := p.price(asset)
+			if err != nil {
+				return nil, err
+			}
+			total = total.Add(amount.Mul(price))
+		}
+
+		assessments = append(assessments, p.assessment(index, "", balance.Neg(), total))
+	}
+
+	return assessments, nil
+}
+
+// price returns price of the asset.
+func (p *Policy) price(asset liability.Asset) (decimal.Decimal, error) {
+	price, found := p.Prices[asset]
+	if !found {
+		return decimal.Decimal{}, fmt.Errorf("%w: %s", ErrUnknownPrice, asset)
+	}
+
+	return price, nil
+}
+
+// assessment compares single exposure with the collateral.
+func (p *Policy) assessment(index uint, asset liability.Asset, exposure, collateral decimal.Decimal) Assessment {
+	breach := exposure.GreaterThan(collateral.Mul(p.MaxRatio))
+
+	return Assessment{
+		Participant: index,
+		Asset:       asset,
+		Exposure:    exposure,
+		Collateral:  collateral,
+		Breach:      breach,
+		TopUp:       breach || p.TopUpRatio.IsPositive() && exposure.GreaterThan(collateral.Mul(p.TopUpRatio)),
+	}
+}
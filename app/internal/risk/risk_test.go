@@ -0,0 +1,107 @@
+package risk
+
+import (
+	"app/internal/liability"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func getLiabilitiesState(t *testing.T) liability.LiabilitiesState {
+	ls := make(liability.LiabilitiesState)
+	ls.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(3))
+	ls.AddPendingLiability(1, 0, "ETH", decimal.NewFromFloat(1))
+	ls.AddPendingLiability(1, 0, "BTC", decimal.NewFromFloat(0.1))
+	err := ls.AddExecutedLiability(0, 1, "ETH", liability.Execution{Amount: decimal.NewFromFloat(1)})
+	assert.NoError(t, err)
+
+	return ls
+}
+
+func getCollateral() Collateral {
+	return Collateral{
+		0: {"ETH": decimal.NewFromFloat(4), "BTC": decimal.Zero},
+		1: {"ETH": decimal.NewFromFloat(1), "BTC": decimal.NewFromFloat(0.1)},
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	_, err := NewPolicy(decimal.Zero, decimal.Zero, nil)
+	assert.ErrorIs(t, err, ErrInvalidRatio)
+
+	_, err = NewPolicy(decimal.NewFromFloat(0.5), decimal.NewFromFloat(0.8), nil)
+	assert.ErrorIs(t, err, ErrInvalidRatio)
+
+	_, err = NewPolicy(decimal.NewFromInt(1), decimal.NewFromFloat(0.8), nil)
+	assert.NoError(t, err)
+}
+
+func TestExposures(t *testing.T) {
+	exposure := Exposures(getLiabilitiesState(t))
+
+	assert.Equal(t, 2, len(exposure))
+	assert.True(t, exposure[0]["ETH"].Equal(decimal.NewFromFloat(2)))
+	assert.True(t, exposure[1]["BTC"].Equal(decimal.NewFromFloat(0.1)))
+	assert.NotContains(t, exposure[1], liability.Asset("ETH"))
+}
+
+func TestAssess(t *testing.T) {
+	ls := getLiabilitiesState(t)
+
+	t.Run("per asset exposure", func(t *testing.T) {
+		policy, err := NewPolicy(decimal.NewFromInt(1), decimal.NewFromFloat(0.4), nil)
+		assert.NoError(t, err)
+
+		assessments, err := policy.Assess(ls, getCollateral())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(assessments))
+
+		assert.Equal(t, uint(0), assessments[0].Participant)
+		assert.Equal(t, liability.Asset("ETH"), assessments[0].Asset)
+		assert.False(t, assessments[0].Breach)
+		assert.True(t, assessments[0].TopUp)
+
+		assert.Equal(t, uint(1), assessments[1].Participant)
+		assert.False(t, assessments[1].Breach)
+		assert.True(t, assessments[1].TopUp)
+
+		assert.NoError(t, policy.Check(ls, getCollateral()))
+	})
+
+	t.Run("exposure exceeds collateral", func(t *testing.T) {
+		policy, err := NewPolicy(decimal.NewFromFloat(0.5), decimal.Zero, nil)
+		assert.NoError(t, err)
+
+		err = policy.Check(ls, getCollateral())
+		assert.ErrorIs(t, err, ErrExposureLimit)
+
+		indexes, err := policy.TopUps(ls, getCollateral())
+		assert.NoError(t, err)
+		assert.Equal(t, []uint{1}, indexes)
+	})
+
+	t.Run("exposure valued with prices", func(t *testing.T) {
+		prices := Prices{"ETH": decimal.NewFromInt(2000), "BTC": decimal.NewFromInt(30000)}
+		policy, err := NewPolicy(decimal.NewFromInt(1), decimal.Zero, prices)
+		assert.NoError(t, err)
+
+		assessments, err := policy.Assess(ls, getCollateral())
+		assert.NoError(t, err)
+
+		// participant 0 owes 2 ETH and is owed 0.1 BTC
+		assert.Equal(t, 1, len(assessments))
+		assert.Equal(t, uint(0), assessments[0].Participant)
+		assert.Equal(t, liability.Asset(""), assessments[0].Asset)
+		assert.True(t, assessments[0].Exposure.Equal(decimal.NewFromInt(1000)))
+		assert.True(t, assessments[0].Collateral.Equal(decimal.NewFromInt(8000)))
+	})
+
+	t.Run("missing price", func(t *testing.T) {
+		policy, err := NewPolicy(decimal.NewFromInt(1), decimal.Zero, Prices{"ETH": decimal.NewFromInt(2000)})
+		assert.NoError(t, err)
+
+		_, err = policy.Assess(ls, getCollateral())
+		assert.ErrorIs(t, err, ErrUnknownPrice)
+	})
+}
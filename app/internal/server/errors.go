@@ -0,0 +1,100 @@
+package server
+
+import (
+	"app/internal/asset"
+	"app/internal/liability"
+	"app/internal/risk"
+	"app/pkg/protocol"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+)
+
+var (
+	errNotFound         = errors.New("server: resource not found")
+	errMethodNotAllowed = errors.New("server: method not allowed")
+)
+
+// errorMapping maps known error to HTTP status and machine readable code.
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+var errorMappings = []errorMapping{
+	{protocol.ErrCompletedState, http.StatusConflict, "completed_state"},
+	{protocol.ErrNotFinalState, http.StatusConflict, "not_final_state"},
+	{protocol.ErrIncompleteState, http.StatusConflict, "incomplete_state"},
+	{protocol.ErrInvalidSignature, http.StatusBadRequest, "invalid_signature"},
+	{protocol.ErrSignatureIsNotInList, http.StatusForbidden, "signature_not_in_list"},
+	{protocol.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount"},
+	{protocol.ErrInvalidTransition, http.StatusUnprocessableEntity, "invalid_transition"},
+	{protocol.ErrNoAdjudicator, http.StatusServiceUnavailable, "adjudicator_unavailable"},
+	{protocol.ErrUnknownParticipant, http.StatusUnprocessableEntity, "unknown_participant"},
+	{protocol.ErrNegativeAllocation, http.StatusUnprocessableEntity, "negative_allocation"},
+	{protocol.ErrDuplicateParticipant, http.StatusUnprocessableEntity, "duplicate_participant"},
+	{protocol.ErrInvalidLockedAmount, http.StatusUnprocessableEntity, "invalid_locked_amount"},
+	{protocol.ErrParticipantIndex, http.StatusUnprocessableEntity, "participant_index"},
+	{protocol.ErrNoFunds, http.StatusUnprocessableEntity, "no_funds"},
+	{protocol.ErrDuplicateChannel, http.StatusConflict, "duplicate_channel"},
+	{protocol.ErrEquivocation, http.StatusConflict, "equivocation"},
+	{protocol.ErrMoverNotSigned, http.StatusConflict, "mover_not_signed"},
+	{asset.ErrUnknownAsset, http.StatusUnprocessableEntity, "unknown_asset"},
+	{asset.ErrAssetNotInChannel, http.StatusUnprocessableEntity, "asset_not_in_channel"},
+	{asset.ErrPrecisionLoss, http.StatusUnprocessableEntity, "precision_loss"},
+	{risk.ErrExposureLimit, http.StatusUnprocessableEntity, "exposure_limit"},
+	{risk.ErrUnknownPrice, http.StatusUnprocessableEntity, "unknown_price"},
+	{protocol.ErrNoAssetRegistry, http.StatusInternalServerError, "no_asset_registry"},
+	{liability.ErrNonExistingLiabilities, http.StatusUnprocessableEntity, "non_existing_liabilities"},
+	{liability.ErrNoPendingLiability, http.StatusUnprocessableEntity, "no_pending_liability"},
+	{liability.ErrInvalidOperation, http.StatusUnprocessableEntity, "invalid_liability_operation"},
+	{liability.ErrDuplicateReference, http.StatusConflict, "duplicate_reference"},
+	{liability.ErrUnknownReference, http.StatusUnprocessableEntity, "unknown_reference"},
+	{liability.ErrInvalidAmount, http.StatusUnprocessableEntity, "invalid_liability_amount"},
+	{ErrChannelNotFound, http.StatusNotFound, "channel_not_found"},
+	{ErrProposalNotFound, http.StatusNotFound, "proposal_not_found"},
+	{ErrUnknownSigner, http.StatusForbidden, "unknown_signer"},
+	{ErrUnknownAccount, http.StatusBadRequest, "unknown_account"},
+	{ErrNoAdjudicator, http.StatusServiceUnavailable, "adjudicator_unavailable"},
+	{ErrInvalidRequest, http.StatusBadRequest, "invalid_request"},
+	{errNotFound, http.StatusNotFound, "not_found"},
+	{errMethodNotAllowed, http.StatusMethodNotAllowed, "method_not_allowed"},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, "timeout"},
+}
+
+// errorResponse represents error returned to the client.
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+// errorBody contains error code and human readable message.
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeError writes structured error response with status mapped from err.
+func writeError(w http.ResponseWriter, err error) {
+	status, code := http.StatusInternalServerError, "internal_error"
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			status, code = m.status, m.code
+			break
+		}
+	}
+
+	writeJSON(w, status, errorResponse{Error: errorBody{Code: code, Message: err.Error()}})
+}
+
+// writeJSON writes body encoded as JSON with given status.
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		log.Printf("server: write response: %v", err)
+	}
+}
@@ -0,0 +1,481 @@
+package server
+
+import (
+	"app/internal/liability"
+	"app/internal/storage"
+	"app/pkg/protocol"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shopspring/decimal"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/types"
+)
+
+// participantRequest represents participant of the channel to be opened.
+// Destination defaults to participant address.
+type participantRequest struct {
+	Address     common.Address `json:"address"`
+	Destination *common.Hash   `json:"destination"`
+	Amount      *big.Int       `json:"amount"`
+}
+
+// createChannelRequest represents init proposal parameters.
+type createChannelRequest struct {
+	Participants []participantRequest `json:"participants"`
+}
+
+// signerRequest represents account which performs the operation.
+type signerRequest struct {
+	Signer common.Address `json:"signer"`
+}
+
+// liabilityRequest represents liability operation applied to proposed state.
+// Operations with reference are applied to the pending liability with that reference.
+type liabilityRequest struct {
+	Type      string          `json:"type"`
+	From      uint            `json:"from"`
+	To        uint            `json:"to"`
+	Asset     liability.Asset `json:"asset"`
+	Amount    decimal.Decimal `json:"amount"`
+	Reference string          `json:"reference,omitempty"`
+	Expiry    int64           `json:"expiry,omitempty"`
+}
+
+// proposeStateRequest represents state proposal parameters.
+type proposeStateRequest struct {
+	Liabilities []liabilityRequest `json:"liabilities"`
+	Final       bool               `json:"final"`
+}
+
+// signatureRequest represents signature of the state made by participant which keys the server doesn't hold.
+type signatureRequest struct {
+	State     state.State   `json:"state"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// allocationView represents outcome allocation.
+type allocationView struct {
+	Destination common.Hash `json:"destination"`
+	Amount      *big.Int    `json:"amount"`
+}
+
+// channelView represents current state of the channel.
+type channelView struct {
+	ID           string                     `json:"id"`
+	Participants []common.Address           `json:"participants"`
+	TurnNum      uint64                     `json:"turnNum"`
+	IsFinal      bool                       `json:"isFinal"`
+	AppData      hexutil.Bytes              `json:"appData"`
+	Liabilities  liability.LiabilitiesState `json:"liabilities"`
+	Allocations  []allocationView           `json:"allocations"`
+}
+
+// signatureView represents signature made by the server.
+type signatureView struct {
+	TurnNum   uint64         `json:"turnNum"`
+	Signer    common.Address `json:"signer"`
+	Signature hexutil.Bytes  `json:"signature"`
+}
+
+// transactionView represents submitted on-chain transaction.
+type transactionView struct {
+	Hash common.Hash `json:"hash"`
+}
+
+// holdingsView represents on-chain holdings of the channel.
+type holdingsView struct {
+	Amount *big.Int `json:"amount"`
+}
+
+// listChannels returns all served channels.
+func (s *Server) listChannels(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	entries := make([]*entry, 0, len(s.channels))
+	for _, e := range s.channels {
+		entries = append(entries, e)
+	}
+	s.mu.Unlock()
+
+	views := make([]channelView, 0, len(entries))
+	for _, e := range entries {
+		view, err := newChannelView(e, e.channel.CurrentState())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		views = append(views, view)
+	}
+
+	writeJSON(w, http.StatusOK, views)
+}
+
+// createChannel opens a new channel from init proposal parameters.
+func (s *Server) createChannel(w http.ResponseWriter, r *http.Request) {
+	var req createChannelRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if len(req.Participants) == 0 {
+		writeError(w, fmt.Errorf("%w: participants are required", ErrInvalidRequest))
+		return
+	}
+
+	var participants []*protocol.Participant
+	record := &storage.Record{ChainID: s.contract.Client.ChainID, AssetAddress: s.contract.AssetAddress}
+	for i, p := range req.Participants {
+		if p.Amount == nil {
+			writeError(w, fmt.Errorf("%w: participant amount is required", ErrInvalidRequest))
+			return
+		}
+
+		destination := types.AddressToDestination(p.Address)
+		if p.Destination != nil {
+			destination = types.Destination(*p.Destination)
+		}
+
+		participants = append(participants, protocol.NewParticipant(p.Address, destination, uint(i), p.Amount))
+		record.Participants = append(record.Participants, storage.Participant{
+			Address:      p.Address,
+			Destination:  common.Hash(destination),
+			LockedAmount: p.Amount,
+			Index:        uint(i),
+		})
+	}
+
+	ch, err := s.initChannel(participants, nil)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	record.ID = ch.ID().String()
+	record.ChannelNonce = ch.CurrentState().ChannelNonce
+
+	err = s.manager.Add(ch)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	err = s.store.Save(record)
+	if err != nil {
+		s.manager.Remove(ch.ID())
+		writeError(w, err)
+		return
+	}
+
+	e := &entry{channel: ch, participants: participants, record: record}
+	s.mu.Lock()
+	s.channels[record.ID] = e
+	s.mu.Unlock()
+
+	view, err := newChannelView(e, e.channel.CurrentState())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, view)
+}
+
+// getChannel returns current state of the channel.
+func (s *Server) getChannel(w http.ResponseWriter, r *http.Request, e *entry) {
+	view, err := newChannelView(e, e.channel.CurrentState())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, view)
+}
+
+// getHoldings returns on-chain holdings of the channel.
+func (s *Server) getHoldings(w http.ResponseWriter, r *http.Request, e *entry) {
+	if s.contract.Client.Adjudicator == nil {
+		writeError(w, ErrNoAdjudicator)
+		return
+	}
+
+	holdings, err := e.channel.CheckHoldings(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, holdingsView{Amount: holdings})
+}
+
+// getEvidence returns signed states of the channel for dispute, the evidence is verified offline.
+func (s *Server) getEvidence(w http.ResponseWriter, r *http.Request, e *entry) {
+	evidence, err := e.channel.Evidence()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, evidence)
+}
+
+// approveInit signs prefund state on behalf of the signer.
+func (s *Server) approveInit(w http.ResponseWriter, r *http.Request, e *entry) {
+	s.sign(w, r, e, e.channel.PreFundState(), e.channel.ApproveInitChannel)
+}
+
+// approveFunding signs postfund state on behalf of the signer.
+func (s *Server) approveFunding(w http.ResponseWriter, r *http.Request, e *entry) {
+	s.sign(w, r, e, e.channel.PostFundState(), e.channel.ApproveChannelFunding)
+}
+
+// signState signs proposed state with the given turn num on behalf of the signer.
+func (s *Server) signState(w http.ResponseWriter, r *http.Request, e *entry, turnNum uint64) {
+	if e.proposal == nil || e.proposal.TurnNum() != turnNum {
+		writeError(w, ErrProposalNotFound)
+		return
+	}
+
+	s.sign(w, r, e, e.proposal.State(), func(privateKey []byte) (state.Signature, error) {
+		return e.channel.SignState(r.Context(), e.proposal, privateKey)
+	})
+}
+
+// sign performs signing operation of the signed state and persists produced signature against it.
+func (s *Server) sign(w http.ResponseWriter, r *http.Request, e *entry, signed state.State, signFn func([]byte) (state.Signature, error)) {
+	var req signerRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	key, err := s.key(req.Signer)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	signature, err := signFn(key)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	e.record.AddSignature(signed, signature)
+	err = s.store.Save(e.record)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, signatureView{
+		TurnNum:   signed.TurnNum,
+		Signer:    req.Signer,
+		Signature: protocol.EncodeSignature(signature),
+	})
+}
+
+// addSignature adds signature of the state submitted by another participant and persists it.
+func (s *Server) addSignature(w http.ResponseWriter, r *http.Request, e *entry) {
+	var req signatureRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	signature, err := protocol.DecodeSignature(req.Signature)
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+		return
+	}
+
+	signer, err := req.State.RecoverSigner(signature)
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+		return
+	}
+
+	err = e.channel.AddSignature(&req.State, signature)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	e.record.AddSignature(req.State, signature)
+	err = s.store.Save(e.record)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, signatureView{
+		TurnNum:   req.State.TurnNum,
+		Signer:    signer,
+		Signature: protocol.EncodeSignature(signature),
+	})
+}
+
+// fundChannel deposits participant funds to the channel.
+func (s *Server) fundChannel(w http.ResponseWriter, r *http.Request, e *entry) {
+	s.transact(w, r, e, func(p *protocol.Participant, privateKey []byte) (common.Hash, error) {
+		tx, err := e.channel.FundChannel(r.Context(), p, privateKey)
+		if err != nil {
+			return common.Hash{}, err
+		}
+
+		return tx.Hash(), nil
+	})
+}
+
+// conclude finalizes the channel with collected signatures of the final state.
+func (s *Server) conclude(w http.ResponseWriter, r *http.Request, e *entry) {
+	s.transact(w, r, e, func(p *protocol.Participant, privateKey []byte) (common.Hash, error) {
+		signatures, err := e.record.Signatures(e.channel.CurrentState())
+		if err != nil {
+			return common.Hash{}, err
+		}
+
+		tx, err := e.channel.Conclude(r.Context(), p, privateKey, signatures)
+		if err != nil {
+			return common.Hash{}, err
+		}
+
+		return tx.Hash(), nil
+	})
+}
+
+// transact submits on-chain transaction on behalf of the signer.
+func (s *Server) transact(w http.ResponseWriter, r *http.Request, e *entry, txFn func(*protocol.Participant, []byte) (common.Hash, error)) {
+	var req signerRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if s.contract.Client.Adjudicator == nil {
+		writeError(w, ErrNoAdjudicator)
+		return
+	}
+
+	p, err := e.participant(req.Signer)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	key, err := s.key(req.Signer)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	hash, err := txFn(p, key)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, transactionView{Hash: hash})
+}
+
+// proposeState constructs new state with requested liabilities.
+func (s *Server) proposeState(w http.ResponseWriter, r *http.Request, e *entry) {
+	var req proposeStateRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	sp, err := e.channel.ProposeState()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	for _, l := range req.Liabilities {
+		switch {
+		case l.Type == "pending" && (l.Reference != "" || l.Expiry != 0):
+			err = sp.PendingLiabilityWithReference(l.From, l.To, l.Asset, l.Amount, l.Reference, l.Expiry)
+		case l.Type == "pending":
+			err = sp.PendingLiability(l.From, l.To, l.Asset, l.Amount)
+		case l.Type == "executed" && l.Reference != "":
+			err = sp.ExecutedLiabilityByReference(l.Reference, l.Amount)
+		case l.Type == "executed":
+			err = sp.ExecutedLiability(l.From, l.To, l.Asset, l.Amount)
+		case l.Type == "revert" && l.Reference != "":
+			err = sp.RevertLiabilityByReference(l.Reference)
+		case l.Type == "revert":
+			err = sp.RevertLiability(l.From, l.To, l.Asset, l.Amount)
+		default:
+			err = fmt.Errorf("%w: unknown liability type %q", ErrInvalidRequest, l.Type)
+		}
+
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	err = sp.ApproveLiabilities()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if req.Final {
+		err = sp.SetFinal()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	proposed := sp.State()
+	e.record.Proposal = &proposed
+	err = s.store.Save(e.record)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	e.proposal = sp
+
+	view, err := newChannelView(e, proposed)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, view)
+}
+
+// newChannelView builds view of the channel in the given state.
+func newChannelView(e *entry, current state.State) (channelView, error) {
+	sp, err := protocol.NewStateProposal(&current)
+	if err != nil {
+		return channelView{}, err
+	}
+
+	var allocations []allocationView
+	for _, a := range current.Outcome[0].Allocations {
+		allocations = append(allocations, allocationView{Destination: common.Hash(a.Destination), Amount: a.Amount})
+	}
+
+	return channelView{
+		ID:           e.record.ID,
+		Participants: current.Participants,
+		TurnNum:      current.TurnNum,
+		IsFinal:      current.IsFinal,
+		AppData:      hexutil.Bytes(current.AppData),
+		Liabilities:  sp.LiabilityState(),
+		Allocations:  allocations,
+	}, nil
+}
+
+// decodeRequest decodes JSON request body, writing error response on failure.
+func decodeRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: %v", ErrInvalidRequest, err))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,284 @@
+package server
+
+import (
+	"app/internal/storage"
+	"app/pkg/protocol"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/types"
+)
+
+var (
+	ErrChannelNotFound  = errors.New("server: channel not found")
+	ErrProposalNotFound = errors.New("server: state proposal not found")
+	ErrUnknownSigner    = errors.New("server: private key for signer is not configured")
+	ErrUnknownAccount   = errors.New("server: account is not a channel participant")
+	ErrNoAdjudicator    = errors.New("server: adjudicator is not configured")
+	ErrAssetMismatch    = errors.New("server: stored channel uses different chain or asset")
+	ErrInvalidRecord    = errors.New("server: stored channel record is invalid")
+	ErrInvalidRequest   = errors.New("server: invalid request")
+)
+
+// DefaultRequestTimeout bounds chain requests made while a request is served.
+const DefaultRequestTimeout = 30 * time.Second
+
+// entry represents state channel served by the server.
+type entry struct {
+	channel      *protocol.Channel
+	participants []*protocol.Participant
+	proposal     *protocol.StateProposal
+	record       *storage.Record
+}
+
+// Server exposes protocol.Channel operations over HTTP with JSON payloads.
+// Requests of the same channel are serialized by the channel manager, requests of different channels run concurrently.
+type Server struct {
+	contract *protocol.Contract
+	store    storage.Store
+	keys     map[common.Address][]byte
+	timeout  time.Duration
+	manager  *protocol.ChannelManager
+
+	// mu guards channels index only, it isn't held while channel operations are performed.
+	mu       sync.Mutex
+	channels map[string]*entry
+}
+
+// New returns a new Server and restores channels which were persisted in the store.
+// Keys contain private keys of the accounts the server signs on behalf of.
+func New(contract *protocol.Contract, store storage.Store, keys map[common.Address][]byte) (*Server, error) {
+	s := &Server{
+		contract: contract,
+		store:    store,
+		keys:     keys,
+		timeout:  DefaultRequestTimeout,
+		manager:  protocol.NewChannelManager(),
+		channels: make(map[string]*entry),
+	}
+
+	records, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		e, err := s.restore(record)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.manager.Add(e.channel)
+		if err != nil {
+			return nil, err
+		}
+		s.channels[record.ID] = e
+	}
+
+	return s, nil
+}
+
+// ServeHTTP routes request to the appropriate handler.
+// Request context is bound to the server request timeout, so chain requests don't block the handler forever.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+	defer cancel()
+	r = r.WithContext(ctx)
+
+	path := strings.Trim(r.URL.Path, "/")
+	segments := strings.Split(path, "/")
+	if segments[0] != "channels" {
+		writeError(w, errNotFound)
+		return
+	}
+	segments = segments[1:]
+
+	switch {
+	case len(segments) == 0:
+		s.route(w, r, map[string]http.HandlerFunc{
+			http.MethodGet:  s.listChannels,
+			http.MethodPost: s.createChannel,
+		})
+	case len(segments) == 1:
+		s.route(w, r, map[string]http.HandlerFunc{
+			http.MethodGet: s.withChannel(segments[0], s.getChannel),
+		})
+	case len(segments) == 2:
+		handlers := map[string]func(http.ResponseWriter, *http.Request, *entry){
+			"holdings":   s.getHoldings,
+			"prefund":    s.approveInit,
+			"fund":       s.fundChannel,
+			"postfund":   s.approveFunding,
+			"states":     s.proposeState,
+			"conclude":   s.conclude,
+			"evidence":   s.getEvidence,
+			"signatures": s.addSignature,
+		}
+
+		handler, ok := handlers[segments[1]]
+		if !ok {
+			writeError(w, errNotFound)
+			return
+		}
+
+		method := http.MethodPost
+		if segments[1] == "holdings" || segments[1] == "evidence" {
+			method = http.MethodGet
+		}
+		s.route(w, r, map[string]http.HandlerFunc{method: s.withChannel(segments[0], handler)})
+	case len(segments) == 4 && segments[1] == "states" && segments[3] == "signatures":
+		turnNum, err := strconv.ParseUint(segments[2], 10, 64)
+		if err != nil {
+			writeError(w, ErrInvalidRequest)
+			return
+		}
+
+		s.route(w, r, map[string]http.HandlerFunc{
+			http.MethodPost: s.withChannel(segments[0], func(w http.ResponseWriter, r *http.Request, e *entry) {
+				s.signState(w, r, e, turnNum)
+			}),
+		})
+	default:
+		writeError(w, errNotFound)
+	}
+}
+
+// route calls handler registered for request method.
+func (s *Server) route(w http.ResponseWriter, r *http.Request, handlers map[string]http.HandlerFunc) {
+	handler, ok := handlers[r.Method]
+	if !ok {
+		writeError(w, errMethodNotAllowed)
+		return
+	}
+
+	handler(w, r)
+}
+
+// withChannel looks up the channel and calls handler holding the channel lock of the manager.
+// Waiting for the lock is bound to the request context.
+func (s *Server) withChannel(id string, handler func(http.ResponseWriter, *http.Request, *entry)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		e, ok := s.channels[channelKey(id)]
+		s.mu.Unlock()
+
+		if !ok {
+			writeError(w, ErrChannelNotFound)
+			return
+		}
+
+		err := s.manager.Do(r.Context(), e.channel.ID(), func(*protocol.Channel) error {
+			handler(w, r, e)
+			return nil
+		})
+		if err != nil {
+			writeError(w, err)
+		}
+	}
+}
+
+// restore rebuilds state channel and its pending state proposal from the stored record.
+func (s *Server) restore(record *storage.Record) (*entry, error) {
+	if record.ChainID == nil || record.ChannelNonce == nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRecord, record.ID)
+	}
+
+	if record.ChainID.Cmp(s.contract.Client.ChainID) != 0 || record.AssetAddress != s.contract.AssetAddress {
+		return nil, ErrAssetMismatch
+	}
+
+	var participants []*protocol.Participant
+	for _, p := range record.Participants {
+		participants = append(participants, protocol.NewParticipant(p.Address, types.Destination(p.Destination), p.Index, p.LockedAmount))
+	}
+
+	ch, err := s.initChannel(participants, record.ChannelNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ss := range record.States {
+		st := ss.State
+		for _, signature := range ss.Signatures {
+			err := ch.AddSignature(&st, signature)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	e := &entry{channel: ch, participants: participants, record: record}
+	if record.Proposal != nil {
+		// state.Clone drops app data, which holds liabilities of the proposal, so it is copied explicitly.
+		proposed := record.Proposal.Clone()
+		proposed.AppData = append(types.Bytes(nil), record.Proposal.AppData...)
+		e.proposal, err = protocol.NewStateProposal(&proposed)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// initChannel builds init proposal and opens channel on behalf of the first participant server holds key for.
+func (s *Server) initChannel(participants []*protocol.Participant, channelNonce *types.Uint256) (*protocol.Channel, error) {
+	myIndex := -1
+	for i, p := range participants {
+		if _, ok := s.keys[p.Address]; ok {
+			myIndex = i
+			break
+		}
+	}
+
+	if myIndex < 0 {
+		return nil, ErrUnknownSigner
+	}
+
+	prop := protocol.NewInitProposal(participants[0], s.contract)
+	for _, p := range participants[1:] {
+		if err := prop.AddParticipant(p); err != nil {
+			return nil, err
+		}
+	}
+
+	if channelNonce != nil {
+		prop.ChannelNonce = channelNonce
+		prop.State.ChannelNonce = channelNonce
+	}
+
+	return protocol.InitChannel(prop, uint(myIndex))
+}
+
+// key returns private key for the signer.
+func (s *Server) key(signer common.Address) ([]byte, error) {
+	key, ok := s.keys[signer]
+	if !ok {
+		return nil, ErrUnknownSigner
+	}
+
+	return key, nil
+}
+
+// participant returns channel participant with the given address.
+func (e *entry) participant(address common.Address) (*protocol.Participant, error) {
+	for _, p := range e.participants {
+		if p.Address == address {
+			return p, nil
+		}
+	}
+
+	return nil, ErrUnknownAccount
+}
+
+// channelKey normalizes channel id passed by client.
+func channelKey(id string) string {
+	return common.HexToHash(id).String()
+}
@@ -0,0 +1,447 @@
+package server
+
+import (
+	"app/internal/asset"
+	"app/internal/storage"
+	"app/pkg/nitro"
+	"app/pkg/protocol"
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	address1 = common.HexToAddress("0xdd2fd4581271e230360230f9337d5c0430bf44c0")
+	address2 = common.HexToAddress("0x8626f6940e2eb28930efb4cef49b2d1f2c9c1199")
+	keys     = map[common.Address][]byte{
+		address1: common.Hex2Bytes("de9be858da4a475276426320d5e9262ecfc3ba460bfac56360bfa6c4c28b4ee0"),
+		address2: common.Hex2Bytes("df57089febbacf7ba0bc227dafbffa9fc08a93fdc68e1e42411a14efcf23656e"),
+	}
+)
+
+func getServer(t *testing.T, dir string) *Server {
+	store, err := storage.NewFileStore(dir)
+	require.NoError(t, err)
+
+	contract := protocol.NewContract(nitro.Client{ChainID: big.NewInt(2)}, common.HexToAddress("0x"))
+	srv, err := New(contract, store, keys)
+	require.NoError(t, err)
+
+	return srv
+}
+
+func doRequest(t *testing.T, srv *Server, method, path string, body interface{}, resp interface{}) int {
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(method, path, &buf))
+	if resp != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), resp))
+	}
+
+	return rec.Code
+}
+
+func openChannel(t *testing.T, srv *Server) string {
+	var view channelView
+	code := doRequest(t, srv, http.MethodPost, "/channels", createChannelRequest{
+		Participants: []participantRequest{
+			{Address: address1, Amount: big.NewInt(2)},
+			{Address: address2, Amount: big.NewInt(2)},
+		},
+	}, &view)
+	require.Equal(t, http.StatusCreated, code)
+
+	for _, stage := range []string{"prefund", "postfund"} {
+		for _, signer := range []common.Address{address1, address2} {
+			code := doRequest(t, srv, http.MethodPost, "/channels/"+view.ID+"/"+stage, signerRequest{Signer: signer}, nil)
+			require.Equal(t, http.StatusOK, code)
+		}
+	}
+
+	return view.ID
+}
+
+func TestCreateChannel(t *testing.T) {
+	t.Run("successful channel creation", func(t *testing.T) {
+		srv := getServer(t, t.TempDir())
+		id := openChannel(t, srv)
+
+		var view channelView
+		code := doRequest(t, srv, http.MethodGet, "/channels/"+id, nil, &view)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(1), view.TurnNum)
+		assert.Equal(t, []common.Address{address1, address2}, view.Participants)
+	})
+
+	t.Run("no participants", func(t *testing.T) {
+		srv := getServer(t, t.TempDir())
+
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodPost, "/channels", createChannelRequest{}, &resp)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_request", resp.Error.Code)
+	})
+
+	t.Run("no key for any participant", func(t *testing.T) {
+		srv := getServer(t, t.TempDir())
+
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodPost, "/channels", createChannelRequest{
+			Participants: []participantRequest{{Address: common.HexToAddress("0x01"), Amount: big.NewInt(2)}},
+		}, &resp)
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "unknown_signer", resp.Error.Code)
+	})
+	t.Run("duplicate participant", func(t *testing.T) {
+		srv := getServer(t, t.TempDir())
+
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodPost, "/channels", createChannelRequest{
+			Participants: []participantRequest{
+				{Address: address1, Amount: big.NewInt(2)},
+				{Address: address1, Amount: big.NewInt(2)},
+			},
+		}, &resp)
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.Equal(t, "duplicate_participant", resp.Error.Code)
+	})
+}
+
+func TestStateProposal(t *testing.T) {
+	srv := getServer(t, t.TempDir())
+	id := openChannel(t, srv)
+
+	var view channelView
+	code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states", proposeStateRequest{
+		Liabilities: []liabilityRequest{{Type: "pending", From: 0, To: 1, Asset: "ETH", Amount: decimal.NewFromFloat(2)}},
+	}, &view)
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, uint64(2), view.TurnNum)
+	assert.True(t, view.Liabilities[0][1].PendingAmount("ETH").Equal(decimal.NewFromFloat(2)))
+
+	t.Run("sign proposed state", func(t *testing.T) {
+		for _, signer := range []common.Address{address1, address2} {
+			var signature signatureView
+			code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states/2/signatures", signerRequest{Signer: signer}, &signature)
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, 65, len(signature.Signature))
+		}
+	})
+
+	t.Run("sign unknown proposal", func(t *testing.T) {
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states/5/signatures", signerRequest{Signer: address1}, &resp)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "proposal_not_found", resp.Error.Code)
+	})
+
+	t.Run("invalid liability operation", func(t *testing.T) {
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states", proposeStateRequest{
+			Liabilities: []liabilityRequest{{Type: "executed", From: 1, To: 0, Asset: "ETH", Amount: decimal.NewFromFloat(2)}},
+		}, &resp)
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.Equal(t, "non_existing_liabilities", resp.Error.Code)
+	})
+
+	t.Run("negative liability amount", func(t *testing.T) {
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states", proposeStateRequest{
+			Liabilities: []liabilityRequest{{Type: "pending", From: 0, To: 1, Asset: "ETH", Amount: decimal.NewFromFloat(-1)}},
+		}, &resp)
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.Equal(t, "invalid_liability_amount", resp.Error.Code)
+	})
+}
+
+func TestAddSignature(t *testing.T) {
+	srv := getServer(t, t.TempDir())
+	id := openChannel(t, srv)
+
+	code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states", proposeStateRequest{
+		Liabilities: []liabilityRequest{{Type: "pending", From: 0, To: 1, Asset: "ETH", Amount: decimal.NewFromFloat(1)}},
+	}, nil)
+	require.Equal(t, http.StatusCreated, code)
+	code = doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states/2/signatures", signerRequest{Signer: address1}, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	proposed := *srv.channels[id].record.Proposal
+	signature, err := proposed.Sign(keys[address2])
+	require.NoError(t, err)
+
+	t.Run("counterparty signature is added", func(t *testing.T) {
+		var view signatureView
+		code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/signatures", signatureRequest{State: proposed, Signature: protocol.EncodeSignature(signature)}, &view)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, address2, view.Signer)
+		assert.Equal(t, uint64(2), view.TurnNum)
+
+		var evidence protocol.Evidence
+		code = doRequest(t, srv, http.MethodGet, "/channels/"+id+"/evidence", nil, &evidence)
+		assert.Equal(t, http.StatusOK, code)
+		require.Len(t, evidence.States, 3)
+		assert.Len(t, evidence.States[2].Signatures, 2)
+	})
+
+	t.Run("invalid signature encoding", func(t *testing.T) {
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/signatures", signatureRequest{State: proposed, Signature: []byte{1, 2, 3}}, &resp)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_request", resp.Error.Code)
+	})
+
+	t.Run("signature of non participant", func(t *testing.T) {
+		foreign, err := proposed.Sign(common.Hex2Bytes("0caa53ae5ea8ddef6f1ae7ab4e2ee4b2c05f0b7c1b5d8f7b9bfed40b8c6f6d52"))
+		require.NoError(t, err)
+
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/signatures", signatureRequest{State: proposed, Signature: protocol.EncodeSignature(foreign)}, &resp)
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "signature_not_in_list", resp.Error.Code)
+	})
+}
+
+func TestFinalState(t *testing.T) {
+	store, err := storage.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	assets, err := asset.NewRegistry(asset.Info{Symbol: "ETH", Address: common.Address{}, Decimals: 0})
+	require.NoError(t, err)
+	contract := protocol.NewContract(nitro.Client{ChainID: big.NewInt(2)}, common.HexToAddress("0x"))
+	contract.Assets = assets
+	srv, err := New(contract, store, keys)
+	require.NoError(t, err)
+	id := openChannel(t, srv)
+
+	code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states", proposeStateRequest{
+		Liabilities: []liabilityRequest{{Type: "pending", From: 0, To: 1, Asset: "ETH", Amount: decimal.NewFromFloat(1)}},
+	}, nil)
+	require.Equal(t, http.StatusCreated, code)
+	for _, signer := range []common.Address{address1, address2} {
+		code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states/2/signatures", signerRequest{Signer: signer}, nil)
+		require.Equal(t, http.StatusOK, code)
+	}
+
+	var view channelView
+	code = doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states", proposeStateRequest{
+		Liabilities: []liabilityRequest{{Type: "executed", From: 0, To: 1, Asset: "ETH", Amount: decimal.NewFromFloat(1)}},
+		Final:       true,
+	}, &view)
+	require.Equal(t, http.StatusCreated, code)
+	assert.True(t, view.IsFinal)
+	assert.Equal(t, big.NewInt(1), view.Allocations[0].Amount)
+	assert.Equal(t, big.NewInt(3), view.Allocations[1].Amount)
+}
+
+// blockingAdjudicator is an adjudicator which answers calls only when their context is done.
+// Entered is notified when the call is made, if it is set.
+type blockingAdjudicator struct {
+	nitro.StateChannelContract
+	entered chan struct{}
+}
+
+func (a blockingAdjudicator) Holdings(opts *bind.CallOpts, asset common.Address, channelId [32]byte) (*big.Int, error) {
+	if a.entered != nil {
+		close(a.entered)
+	}
+	<-opts.Context.Done()
+	return nil, opts.Context.Err()
+}
+
+func TestErrors(t *testing.T) {
+	srv := getServer(t, t.TempDir())
+	id := openChannel(t, srv)
+
+	t.Run("completed state", func(t *testing.T) {
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/prefund", signerRequest{Signer: address1}, &resp)
+		assert.Equal(t, http.StatusConflict, code)
+		assert.Equal(t, "completed_state", resp.Error.Code)
+	})
+
+	t.Run("channel not found", func(t *testing.T) {
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodGet, "/channels/0x01", nil, &resp)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "channel_not_found", resp.Error.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		code := doRequest(t, srv, http.MethodDelete, "/channels/"+id, nil, nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, code)
+	})
+
+	t.Run("adjudicator is not configured", func(t *testing.T) {
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodGet, "/channels/"+id+"/holdings", nil, &resp)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "adjudicator_unavailable", resp.Error.Code)
+	})
+
+	t.Run("chain request timeout", func(t *testing.T) {
+		srv := getServer(t, t.TempDir())
+		id := openChannel(t, srv)
+		srv.contract.Client.Adjudicator = blockingAdjudicator{}
+		srv.timeout = 10 * time.Millisecond
+
+		var resp errorResponse
+		code := doRequest(t, srv, http.MethodGet, "/channels/"+id+"/holdings", nil, &resp)
+		assert.Equal(t, http.StatusGatewayTimeout, code)
+		assert.Equal(t, "timeout", resp.Error.Code)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	dir := t.TempDir()
+	srv := getServer(t, dir)
+	id := openChannel(t, srv)
+
+	code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states", proposeStateRequest{
+		Liabilities: []liabilityRequest{{Type: "pending", From: 0, To: 1, Asset: "BTC", Amount: decimal.NewFromFloat(0.1)}},
+	}, nil)
+	require.Equal(t, http.StatusCreated, code)
+
+	for _, signer := range []common.Address{address1, address2} {
+		code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states/2/signatures", signerRequest{Signer: signer}, nil)
+		require.Equal(t, http.StatusOK, code)
+	}
+
+	restored := getServer(t, dir)
+
+	var views []channelView
+	code = doRequest(t, restored, http.MethodGet, "/channels", nil, &views)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, len(views))
+	assert.Equal(t, id, views[0].ID)
+	assert.Equal(t, uint64(2), views[0].TurnNum)
+	assert.True(t, views[0].Liabilities[0][1].PendingAmount("BTC").Equal(decimal.NewFromFloat(0.1)))
+
+	var resp errorResponse
+	code = doRequest(t, restored, http.MethodPost, "/channels/"+id+"/postfund", signerRequest{Signer: address1}, &resp)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, "completed_state", resp.Error.Code)
+
+	t.Run("pending proposal is restored", func(t *testing.T) {
+		code := doRequest(t, restored, http.MethodPost, "/channels/"+id+"/states", proposeStateRequest{
+			Liabilities: []liabilityRequest{{Type: "pending", From: 1, To: 0, Asset: "BTC", Amount: decimal.NewFromFloat(0.2)}},
+		}, nil)
+		require.Equal(t, http.StatusCreated, code)
+
+		var signed signatureView
+		code = doRequest(t, restored, http.MethodPost, "/channels/"+id+"/states/3/signatures", signerRequest{Signer: address1}, &signed)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(3), signed.TurnNum)
+
+		restored := getServer(t, dir)
+		code = doRequest(t, restored, http.MethodPost, "/channels/"+id+"/states/3/signatures", signerRequest{Signer: address2}, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		var view channelView
+		code = doRequest(t, restored, http.MethodGet, "/channels/"+id, nil, &view)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(3), view.TurnNum)
+		assert.True(t, view.Liabilities[0][1].PendingAmount("BTC").Equal(decimal.NewFromFloat(0.1)))
+		assert.True(t, view.Liabilities[1][0].PendingAmount("BTC").Equal(decimal.NewFromFloat(0.2)))
+	})
+
+	t.Run("signature of not the latest state is restored", func(t *testing.T) {
+		dir := t.TempDir()
+		srv := getServer(t, dir)
+
+		var view channelView
+		code := doRequest(t, srv, http.MethodPost, "/channels", createChannelRequest{
+			Participants: []participantRequest{
+				{Address: address1, Amount: big.NewInt(2)},
+				{Address: address2, Amount: big.NewInt(2)},
+			},
+		}, &view)
+		require.Equal(t, http.StatusCreated, code)
+
+		for _, stage := range []string{"prefund", "postfund"} {
+			code := doRequest(t, srv, http.MethodPost, "/channels/"+view.ID+"/"+stage, signerRequest{Signer: address1}, nil)
+			require.Equal(t, http.StatusOK, code)
+		}
+
+		var signed signatureView
+		code = doRequest(t, srv, http.MethodPost, "/channels/"+view.ID+"/prefund", signerRequest{Signer: address2}, &signed)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(0), signed.TurnNum)
+
+		restored := getServer(t, dir)
+		code = doRequest(t, restored, http.MethodPost, "/channels/"+view.ID+"/postfund", signerRequest{Signer: address2}, nil)
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("record without chain id", func(t *testing.T) {
+		store, err := storage.NewFileStore(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, store.Save(&storage.Record{ID: "0x01", ChannelNonce: big.NewInt(1)}))
+
+		contract := protocol.NewContract(nitro.Client{ChainID: big.NewInt(2)}, common.HexToAddress("0x"))
+		_, err = New(contract, store, keys)
+		assert.ErrorIs(t, err, ErrInvalidRecord)
+	})
+}
+
+func TestChannelLocking(t *testing.T) {
+	srv := getServer(t, t.TempDir())
+	blocked := openChannel(t, srv)
+	other := openChannel(t, srv)
+
+	entered := make(chan struct{})
+	srv.contract.Client.Adjudicator = blockingAdjudicator{entered: entered}
+	srv.timeout = time.Second
+
+	done := make(chan int)
+	go func() {
+		done <- doRequest(t, srv, http.MethodGet, "/channels/"+blocked+"/holdings", nil, nil)
+	}()
+	<-entered
+
+	// operation of the blocked channel in progress doesn't block other channels
+	code := doRequest(t, srv, http.MethodGet, "/channels/"+other, nil, nil)
+	assert.Equal(t, http.StatusOK, code)
+	code = doRequest(t, srv, http.MethodGet, "/channels", nil, nil)
+	assert.Equal(t, http.StatusOK, code)
+	select {
+	case <-done:
+		t.Fatal("blocked request is completed")
+	default:
+	}
+
+	assert.Equal(t, http.StatusGatewayTimeout, <-done)
+}
+
+func TestEvidence(t *testing.T) {
+	srv := getServer(t, t.TempDir())
+	id := openChannel(t, srv)
+
+	code := doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states", proposeStateRequest{
+		Liabilities: []liabilityRequest{{Type: "pending", From: 0, To: 1, Asset: "ETH", Amount: decimal.NewFromFloat(1)}},
+	}, nil)
+	require.Equal(t, http.StatusCreated, code)
+	code = doRequest(t, srv, http.MethodPost, "/channels/"+id+"/states/2/signatures", signerRequest{Signer: address1}, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	var evidence protocol.Evidence
+	code = doRequest(t, srv, http.MethodGet, "/channels/"+id+"/evidence", nil, &evidence)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, id, evidence.ChannelID.String())
+	require.Len(t, evidence.States, 3)
+	assert.Len(t, evidence.States[2].Signatures, 1)
+	assert.NoError(t, evidence.Verify())
+}
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+)
+
+var (
+	ErrNotFound  = errors.New("storage: record not found")
+	ErrInvalidID = errors.New("storage: invalid record id")
+)
+
+// Store represents persistent storage of state channel records.
+type Store interface {
+	Save(record *Record) error
+	Load(id string) (*Record, error)
+	List() ([]*Record, error)
+}
+
+// Participant stores information about participant which is required to restore init proposal.
+type Participant struct {
+	Address      common.Address `json:"address"`
+	Destination  common.Hash    `json:"destination"`
+	LockedAmount *big.Int       `json:"lockedAmount"`
+	Index        uint           `json:"index"`
+}
+
+// SignedState stores state with signatures collected for it.
+type SignedState struct {
+	State      state.State       `json:"state"`
+	Signatures []state.Signature `json:"signatures"`
+}
+
+// Record represents persisted information about state channel.
+type Record struct {
+	ID           string         `json:"id"`
+	ChainID      *big.Int       `json:"chainId"`
+	ChannelNonce *big.Int       `json:"channelNonce"`
+	AssetAddress common.Address `json:"assetAddress"`
+	Participants []Participant  `json:"participants"`
+	States       []SignedState  `json:"states"`
+	// Proposal is the latest state proposed by the server, it is kept until the next proposal.
+	Proposal *state.State `json:"proposal,omitempty"`
+}
+
+// AddSignature appends signature to the stored state, storing the state first if it isn't known yet.
+// Signature which is already stored for the state is ignored.
+func (r *Record) AddSignature(s state.State, signature state.Signature) {
+	for i := range r.States {
+		if r.States[i].State.Equal(s) {
+			for _, stored := range r.States[i].Signatures {
+				if bytes.Equal(stored.R, signature.R) && bytes.Equal(stored.S, signature.S) && stored.V == signature.V {
+					return
+				}
+			}
+			r.States[i].Signatures = append(r.States[i].Signatures, signature)
+			return
+		}
+	}
+
+	r.States = append(r.States, SignedState{State: s, Signatures: []state.Signature{signature}})
+}
+
+// Signatures returns signatures collected for the given state keyed by signer address.
+func (r *Record) Signatures(s state.State) (map[common.Address]state.Signature, error) {
+	signatures := make(map[common.Address]state.Signature)
+	for _, ss := range r.States {
+		if !ss.State.Equal(s) {
+			continue
+		}
+
+		for _, signature := range ss.Signatures {
+			address, err := s.RecoverSigner(signature)
+			if err != nil {
+				return nil, err
+			}
+			signatures[address] = signature
+		}
+	}
+
+	return signatures, nil
+}
+
+// FileStore keeps every record as a separate JSON file in the directory.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore returns a new FileStore, creating directory if it doesn't exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileStore{dir: dir}, nil
+}
+
+// Save writes record to the file, replacing previous version atomically.
+func (fs *FileStore) Save(record *Record) error {
+	path, err := fs.path(record.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Load reads record with the given id.
+func (fs *FileStore) Load(id string) (*Record, error) {
+	path, err := fs.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return readRecord(path)
+}
+
+// List returns all stored records ordered by id.
+func (fs *FileStore) List() ([]*Record, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(fs.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	records := make([]*Record, 0, len(paths))
+	for _, path := range paths {
+		record, err := readRecord(path)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// path returns file path for the record id.
+func (fs *FileStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", ErrInvalidID
+	}
+
+	return filepath.Join(fs.dir, id+".json"), nil
+}
+
+// readRecord reads and decodes record file.
+func readRecord(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var record Record
+	err = json.Unmarshal(data, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func getRecord() *Record {
+	return &Record{
+		ID:           "0x01",
+		ChainID:      big.NewInt(2),
+		ChannelNonce: big.NewInt(7),
+		Participants: []Participant{
+			{Address: common.HexToAddress("0x01"), Destination: common.HexToHash("0x01"), LockedAmount: big.NewInt(2), Index: 0},
+		},
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	t.Run("save and load record", func(t *testing.T) {
+		store, err := NewFileStore(t.TempDir())
+		assert.NoError(t, err)
+
+		record := getRecord()
+		err = store.Save(record)
+		assert.NoError(t, err)
+
+		loaded, err := store.Load(record.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, record, loaded)
+	})
+
+	t.Run("load non existing record", func(t *testing.T) {
+		store, err := NewFileStore(t.TempDir())
+		assert.NoError(t, err)
+
+		_, err = store.Load("0x02")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("invalid record id", func(t *testing.T) {
+		store, err := NewFileStore(t.TempDir())
+		assert.NoError(t, err)
+
+		_, err = store.Load("../0x02")
+		assert.ErrorIs(t, err, ErrInvalidID)
+	})
+
+	t.Run("list records", func(t *testing.T) {
+		store, err := NewFileStore(t.TempDir())
+		assert.NoError(t, err)
+
+		first, second := getRecord(), getRecord()
+		second.ID = "0x02"
+		assert.NoError(t, store.Save(second))
+		assert.NoError(t, store.Save(first))
+
+		records, err := store.List()
+		assert.NoError(t, err)
+		assert.Equal(t, []*Record{first, second}, records)
+	})
+}
+
+func TestRecordSignatures(t *testing.T) {
+	privateKey := common.Hex2Bytes("de9be858da4a475276426320d5e9262ecfc3ba460bfac56360bfa6c4c28b4ee0")
+	s := state.TestState.Clone()
+
+	signature, err := s.Sign(privateKey)
+	assert.NoError(t, err)
+
+	record := getRecord()
+	record.AddSignature(s, signature)
+	assert.Equal(t, 1, len(record.States))
+
+	signatures, err := record.Signatures(s)
+	assert.NoError(t, err)
+	assert.Equal(t, map[common.Address]state.Signature{
+		common.HexToAddress("0xdd2fd4581271e230360230f9337d5c0430bf44c0"): signature,
+	}, signatures)
+}
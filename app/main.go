@@ -5,9 +5,11 @@ import (
 	"app/internal/parser"
 	"app/pkg/nitro"
 	"app/pkg/protocol"
+	"context"
 	"math/big"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/caitlinelfring/go-env-default"
 	"github.com/ethereum/go-ethereum/common"
@@ -20,6 +22,7 @@ var (
 	Network          = env.GetDefault("NETWORK", "localhost")
 	AssetAddress     = common.HexToAddress("0x0")
 	ParticipantCount = 3
+	DialTimeout      = 30 * time.Second
 )
 
 // State channel examples
@@ -61,8 +64,12 @@ func main() {
 		participantPrivateKeys[participantObj] = common.Hex2Bytes(privateKey)
 	}
 
+	ctx := context.Background()
+
 	// Initialize SC client
-	client, err := nitro.NewClient(contractAddress, NodeUrl)
+	dialCtx, cancel := context.WithTimeout(ctx, DialTimeout)
+	client, err := nitro.NewClient(dialCtx, contractAddress, NodeUrl)
+	cancel()
 	if err != nil {
 		panic(err)
 	}
@@ -71,7 +78,7 @@ func main() {
 	c := protocol.NewContract(client, AssetAddress)
 
 	// Demo example
-	err = examples.Demo(participants, participantPrivateKeys, c)
+	err = examples.Demo(ctx, participants, participantPrivateKeys, c)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,175 @@
+package hashlock
+
+import (
+	"app/pkg/protocol"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+)
+
+// SwapTurnNum is the only turn num of the state which HashLockedSwap app allows to transition to.
+const SwapTurnNum = uint64(4)
+
+var (
+	ErrInvalidTurnNum     = errors.New("hashlock: swap must be made at turn 4")
+	ErrAllocationsCount   = errors.New("hashlock: outcome must have two allocations")
+	ErrIncorrectPreImage  = errors.New("hashlock: incorrect preimage")
+	ErrDestinationChanged = errors.New("hashlock: destinations may not change")
+	ErrAmountsNotPermuted = errors.New("hashlock: amounts must be permuted")
+	ErrHashChanged        = errors.New("hashlock: hash may not change")
+)
+
+var (
+	appDataTy, _ = abi.NewType("tuple", "struct AppData", []abi.ArgumentMarshaling{
+		{Name: "h", Type: "bytes32"},
+		{Name: "preImage", Type: "bytes"},
+	})
+
+	// arguments describe ABI layout of app data, which is read in Solidity as
+	// abi.decode(appData, (AppData)) where struct AppData { bytes32 h; bytes preImage; }.
+	arguments = abi.Arguments{{Type: appDataTy}}
+)
+
+// AppData represents app data of HashLockedSwap app: hash of the lock and preimage revealed at the swap.
+type AppData struct {
+	H        [32]byte
+	PreImage []byte
+}
+
+// Hash returns hash which locks the swap with the preimage.
+func Hash(preImage []byte) [32]byte {
+	return sha256.Sum256(preImage)
+}
+
+// Codec is the protocol.AppCodec of HashLockedSwap app, a conditional transfer of the whole channel
+// balance between two participants, which is unlocked by revealing the preimage of the hash.
+type Codec struct{}
+
+// Decode returns AppData decoded from app data, empty app data is decoded to empty AppData.
+func (Codec) Decode(appData []byte) (interface{}, error) {
+	if len(appData) == 0 {
+		return AppData{}, nil
+	}
+
+	values, err := arguments.Unpack(appData)
+	if err != nil {
+		return nil, err
+	}
+
+	return *abi.ConvertType(values[0], new(AppData)).(*AppData), nil
+}
+
+// Encode returns app data of AppData.
+func (Codec) Encode(app interface{}) ([]byte, error) {
+	appData, ok := app.(AppData)
+	if !ok {
+		return nil, protocol.ErrInvalidAppData
+	}
+
+	if appData.PreImage == nil {
+		appData.PreImage = []byte{}
+	}
+
+	return arguments.Pack(appData)
+}
+
+// ValidTransition checks both apps are AppData and hash is unchanged.
+func (Codec) ValidTransition(from, to interface{}) error {
+	fromApp, ok := from.(AppData)
+	if !ok {
+		return protocol.ErrInvalidAppData
+	}
+	toApp, ok := to.(AppData)
+	if !ok {
+		return protocol.ErrInvalidAppData
+	}
+
+	if fromApp.H != ([32]byte{}) && toApp.H != fromApp.H {
+		return ErrHashChanged
+	}
+
+	return nil
+}
+
+// ValidStateTransition checks the swap transition with the same rules as HashLockedSwap.validTransition.
+// States before and after the swap are supported only by signatures of all participants, so they aren't checked.
+func (c Codec) ValidStateTransition(from, to state.State) error {
+	if to.TurnNum != SwapTurnNum {
+		return nil
+	}
+
+	fromApp, err := c.Decode(from.AppData)
+	if err != nil {
+		return err
+	}
+
+	toApp, err := c.Decode(to.AppData)
+	if err != nil {
+		return err
+	}
+
+	return ValidTransition(from.Outcome, to.Outcome, fromApp.(AppData), toApp.(AppData), to.TurnNum)
+}
+
+// ValidTransition checks transition with the same rules as HashLockedSwap.validTransition:
+// transition is to the swap turn, preimage matches the hash, destinations are unchanged
+// and amounts of two participants are permuted.
+func ValidTransition(a, b outcome.Exit, appDataA, appDataB AppData, turnNumB uint64) error {
+	if turnNumB != SwapTurnNum {
+		return ErrInvalidTurnNum
+	}
+
+	if len(a) == 0 || len(b) == 0 || len(a[0].Allocations) != 2 || len(b[0].Allocations) != 2 {
+		return ErrAllocationsCount
+	}
+	allocationsA, allocationsB := a[0].Allocations, b[0].Allocations
+
+	if Hash(appDataB.PreImage) != appDataA.H {
+		return ErrIncorrectPreImage
+	}
+
+	if allocationsA[0].Destination != allocationsB[0].Destination || allocationsA[1].Destination != allocationsB[1].Destination {
+		return ErrDestinationChanged
+	}
+
+	if allocationsA[0].Amount.Cmp(allocationsB[1].Amount) != 0 || allocationsA[1].Amount.Cmp(allocationsB[0].Amount) != 0 {
+		return ErrAmountsNotPermuted
+	}
+
+	return nil
+}
+
+// Lock sets hash of the lock into proposed state app data.
+func Lock(sp *protocol.StateProposal, h [32]byte) error {
+	return sp.SetApp(AppData{H: h})
+}
+
+// Reveal reveals preimage of the lock in proposed swap state and permutes amounts of two participants.
+func Reveal(sp *protocol.StateProposal, preImage []byte) error {
+	if sp.TurnNum() != SwapTurnNum {
+		return ErrInvalidTurnNum
+	}
+
+	app, ok := sp.App().(AppData)
+	if !ok {
+		return protocol.ErrInvalidAppData
+	}
+
+	if Hash(preImage) != app.H {
+		return ErrIncorrectPreImage
+	}
+
+	exit := sp.Outcome()
+	if len(exit) == 0 || len(exit[0].Allocations) != 2 {
+		return ErrAllocationsCount
+	}
+
+	allocations := exit[0].Allocations
+	allocations[0].Amount, allocations[1].Amount = allocations[1].Amount, allocations[0].Amount
+	sp.SetOutcome(exit)
+
+	return sp.SetApp(AppData{H: app.H, PreImage: append([]byte{}, preImage...)})
+}
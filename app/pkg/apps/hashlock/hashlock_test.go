@@ -0,0 +1,216 @@
+package hashlock
+
+import (
+	"app/pkg/nitro"
+	"app/pkg/nitro/simulated"
+	"app/pkg/protocol"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	"github.com/statechannels/go-nitro/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	alice = protocol.NewParticipant(common.HexToAddress("0xdd2fd4581271e230360230f9337d5c0430bf44c0"), types.Destination(common.HexToHash("0xdd2fd4581271e230360230f9337d5c0430bf44c0")), uint(0), big.NewInt(10))
+	bob   = protocol.NewParticipant(common.HexToAddress("0x8626f6940e2eb28930efb4cef49b2d1f2c9c1199"), types.Destination(common.HexToHash("0x8626f6940e2eb28930efb4cef49b2d1f2c9c1199")), uint(1), big.NewInt(0))
+	keys  = map[*protocol.Participant][]byte{
+		alice: common.Hex2Bytes("de9be858da4a475276426320d5e9262ecfc3ba460bfac56360bfa6c4c28b4ee0"),
+		bob:   common.Hex2Bytes("df57089febbacf7ba0bc227dafbffa9fc08a93fdc68e1e42411a14efcf23656e"),
+	}
+	preImage = []byte("secret")
+)
+
+func getExit(amounts ...int64) outcome.Exit {
+	destinations := []types.Destination{alice.Destination, bob.Destination, types.Destination(common.HexToHash("0x03"))}
+	var allocations outcome.Allocations
+	for i, amount := range amounts {
+		allocations = append(allocations, outcome.Allocation{Destination: destinations[i], Amount: big.NewInt(amount)})
+	}
+
+	return outcome.Exit{{Asset: common.HexToAddress("0x"), Allocations: allocations}}
+}
+
+// getLockedChannel returns funded channel between payer and payee locked with hash at turn 3.
+func getLockedChannel(t *testing.T, payer, payee *protocol.Participant, asset common.Address, amount int64) *protocol.Channel {
+	contract := protocol.NewContract(nitro.Client{ChainID: big.NewInt(2)}, asset)
+	proposal := protocol.NewInitProposal(protocol.NewParticipant(payer.Address, payer.Destination, 0, big.NewInt(amount)), contract)
+	require.NoError(t, proposal.AddParticipant(protocol.NewParticipant(payee.Address, payee.Destination, 1, big.NewInt(0))))
+	proposal.SetApp(common.HexToAddress("0x0b"), Codec{})
+
+	ch, err := protocol.InitChannel(proposal, 0)
+	require.NoError(t, err)
+
+	signers := []*protocol.Participant{payer, payee}
+	for _, p := range signers {
+		_, err := ch.ApproveInitChannel(keys[p])
+		require.NoError(t, err)
+	}
+	for _, p := range signers {
+		_, err := ch.ApproveChannelFunding(keys[p])
+		require.NoError(t, err)
+	}
+
+	for turnNum := 2; turnNum < int(SwapTurnNum); turnNum++ {
+		sp, err := ch.ProposeState()
+		require.NoError(t, err)
+		require.NoError(t, Lock(sp, Hash(preImage)))
+
+		for _, p := range signers {
+			_, err := ch.SignState(context.Background(), sp, keys[p])
+			require.NoError(t, err)
+		}
+	}
+
+	return ch
+}
+
+func TestCodec(t *testing.T) {
+	codec := Codec{}
+
+	app, err := codec.Decode([]byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, AppData{}, app)
+
+	expected := AppData{H: Hash(preImage), PreImage: preImage}
+	appData, err := codec.Encode(expected)
+	assert.NoError(t, err)
+
+	app, err = codec.Decode(appData)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, app)
+
+	_, err = codec.Encode(uint64(1))
+	assert.ErrorIs(t, err, protocol.ErrInvalidAppData)
+	assert.ErrorIs(t, codec.ValidTransition(AppData{H: Hash(preImage)}, AppData{H: Hash([]byte("other"))}), ErrHashChanged)
+}
+
+// transitionCases returns swap transitions with expected result of HashLockedSwap.validTransition.
+func transitionCases() []struct {
+	name     string
+	a, b     outcome.Exit
+	appDataB AppData
+	turnNumB uint64
+	err      error
+} {
+	locked := AppData{H: Hash(preImage)}
+	revealed := AppData{H: Hash(preImage), PreImage: preImage}
+	wrongDestination := getExit(0, 10)
+	wrongDestination[0].Allocations[1].Destination = types.Destination(common.HexToHash("0x01"))
+
+	return []struct {
+		name     string
+		a, b     outcome.Exit
+		appDataB AppData
+		turnNumB uint64
+		err      error
+	}{
+		{"valid swap", getExit(10, 0), getExit(0, 10), revealed, 4, nil},
+		{"invalid turn num", getExit(10, 0), getExit(0, 10), revealed, 5, ErrInvalidTurnNum},
+		{"missing preimage", getExit(10, 0), getExit(0, 10), locked, 4, ErrIncorrectPreImage},
+		{"incorrect preimage", getExit(10, 0), getExit(0, 10), AppData{PreImage: []byte("other")}, 4, ErrIncorrectPreImage},
+		{"amounts not permuted", getExit(10, 0), getExit(5, 5), revealed, 4, ErrAmountsNotPermuted},
+		{"destination changed", getExit(10, 0), wrongDestination, revealed, 4, ErrDestinationChanged},
+		{"three allocations", getExit(10, 0), getExit(0, 10, 0), revealed, 4, ErrAllocationsCount},
+	}
+}
+
+func TestValidTransition(t *testing.T) {
+	appDataA := AppData{H: Hash(preImage)}
+
+	for _, test := range transitionCases() {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidTransition(test.a, test.b, appDataA, test.appDataB, test.turnNumB)
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestAtomicSwap(t *testing.T) {
+	// alice sells 10 units of asset A for 3 units of asset B owned by bob
+	channelA := getLockedChannel(t, alice, bob, common.HexToAddress("0x0a"), 10)
+	channelB := getLockedChannel(t, bob, alice, common.HexToAddress("0x0b"), 3)
+
+	// alice reveals preimage to receive asset B
+	swapB, err := channelB.ProposeState()
+	require.NoError(t, err)
+
+	t.Run("swap can't be made without preimage", func(t *testing.T) {
+		locked := swapB.Outcome()
+		assert.ErrorIs(t, Reveal(swapB, []byte("other")), ErrIncorrectPreImage)
+
+		exit := swapB.Outcome()
+		exit[0].Allocations[0].Amount, exit[0].Allocations[1].Amount = big.NewInt(0), big.NewInt(3)
+		swapB.SetOutcome(exit)
+		_, err := channelB.SignState(context.Background(), swapB, keys[bob])
+		assert.ErrorIs(t, err, protocol.ErrInvalidTransition)
+
+		swapB.SetOutcome(locked)
+	})
+
+	require.NoError(t, Reveal(swapB, preImage))
+	for _, p := range []*protocol.Participant{bob, alice} {
+		_, err := channelB.SignState(context.Background(), swapB, keys[p])
+		require.NoError(t, err)
+	}
+
+	// bob learns preimage from channel B and completes swap in channel A
+	revealed := swapB.App().(AppData).PreImage
+	swapA, err := channelA.ProposeState()
+	require.NoError(t, err)
+	require.NoError(t, Reveal(swapA, revealed))
+	for _, p := range []*protocol.Participant{alice, bob} {
+		_, err := channelA.SignState(context.Background(), swapA, keys[p])
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, big.NewInt(10), swapA.Outcome()[0].Allocations[1].Amount)
+	assert.Equal(t, big.NewInt(3), swapB.Outcome()[0].Allocations[1].Amount)
+}
+
+func TestValidTransitionOnChain(t *testing.T) {
+	artifact, err := simulated.LoadArtifact("HashLockedSwap")
+	require.NoError(t, err)
+
+	backend, err := simulated.NewBackend()
+	require.NoError(t, err)
+	defer backend.Close()
+
+	_, app, err := backend.Deploy(artifact)
+	require.NoError(t, err)
+
+	codec := Codec{}
+	appDataA, err := codec.Encode(AppData{H: Hash(preImage)})
+	require.NoError(t, err)
+
+	for _, test := range transitionCases() {
+		t.Run(test.name, func(t *testing.T) {
+			appDataB, err := codec.Encode(test.appDataB)
+			require.NoError(t, err)
+			outcomeA, err := test.a.Encode()
+			require.NoError(t, err)
+			outcomeB, err := test.b.Encode()
+			require.NoError(t, err)
+
+			var out []interface{}
+			err = app.Call(&bind.CallOpts{}, &out, "validTransition",
+				nitro.IForceMoveAppVariablePart{Outcome: outcomeA, AppData: appDataA},
+				nitro.IForceMoveAppVariablePart{Outcome: outcomeB, AppData: appDataB},
+				new(big.Int).SetUint64(test.turnNumB),
+				big.NewInt(2),
+			)
+
+			local := ValidTransition(test.a, test.b, AppData{H: Hash(preImage)}, test.appDataB, test.turnNumB)
+			assert.Equal(t, local == nil, err == nil, "local: %v, on-chain: %v", local, err)
+		})
+	}
+}
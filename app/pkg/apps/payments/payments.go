@@ -0,0 +1,124 @@
+package payments
+
+import (
+	"app/pkg/protocol"
+	"errors"
+	"math/big"
+
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+)
+
+var (
+	ErrSingleAsset         = errors.New("payments: only one asset allowed")
+	ErrAllocationsCount    = errors.New("payments: number of allocations differs from number of participants")
+	ErrNotSimpleAllocation = errors.New("payments: not a simple allocation")
+	ErrDestinationChanged  = errors.New("payments: destinations may not change")
+	ErrNonMoverDecreased   = errors.New("payments: nonmover balance decreased")
+	ErrTotalChanged        = errors.New("payments: total allocated cannot change")
+	ErrInvalidAmount       = errors.New("payments: amount must be positive")
+	ErrInvalidPayee        = errors.New("payments: payee must be another participant")
+	ErrInsufficientBalance = errors.New("payments: mover balance is less than amount")
+)
+
+// Codec is the protocol.AppCodec of SingleAssetPayments app, a payment channel with a single asset,
+// where participant moving at the turn can only decrease own allocation.
+// App data isn't used by the app, so it is decoded to nil app.
+type Codec struct{}
+
+// Decode returns nil app.
+func (Codec) Decode(appData []byte) (interface{}, error) {
+	return nil, nil
+}
+
+// Encode returns empty app data.
+func (Codec) Encode(app interface{}) ([]byte, error) {
+	if app != nil {
+		return nil, protocol.ErrInvalidAppData
+	}
+
+	return []byte{}, nil
+}
+
+// ValidTransition allows any transition between apps, since app data isn't used.
+func (Codec) ValidTransition(from, to interface{}) error {
+	return nil
+}
+
+// ValidStateTransition checks transition between outcomes of the states.
+func (Codec) ValidStateTransition(from, to state.State) error {
+	return ValidTransition(from.Outcome, to.Outcome, to.TurnNum, len(to.Participants))
+}
+
+// ValidTransition checks transition between outcomes with the same rules as SingleAssetPayments.validTransition:
+// outcomes have a single asset with simple allocation for each participant, destinations and total
+// are unchanged and allocations of all participants except the mover haven't decreased.
+func ValidTransition(a, b outcome.Exit, turnNumB uint64, nParticipants int) error {
+	if len(a) != 1 || len(b) != 1 {
+		return ErrSingleAsset
+	}
+
+	allocationsA, allocationsB := a[0].Allocations, b[0].Allocations
+	if len(allocationsA) != nParticipants || len(allocationsB) != nParticipants {
+		return ErrAllocationsCount
+	}
+
+	for i := 0; i < nParticipants; i++ {
+		if allocationsA[i].AllocationType != outcome.NormalAllocationType || allocationsB[i].AllocationType != outcome.NormalAllocationType {
+			return ErrNotSimpleAllocation
+		}
+	}
+
+	mover := Mover(turnNumB, nParticipants)
+	sumA, sumB := new(big.Int), new(big.Int)
+	for i := 0; i < nParticipants; i++ {
+		if allocationsB[i].Destination != allocationsA[i].Destination {
+			return ErrDestinationChanged
+		}
+
+		sumA.Add(sumA, allocationsA[i].Amount)
+		sumB.Add(sumB, allocationsB[i].Amount)
+		if uint(i) != mover && allocationsB[i].Amount.Cmp(allocationsA[i].Amount) < 0 {
+			return ErrNonMoverDecreased
+		}
+	}
+
+	if sumA.Cmp(sumB) != 0 {
+		return ErrTotalChanged
+	}
+
+	return nil
+}
+
+// Mover returns index of participant who moves at the given turn.
+func Mover(turnNum uint64, nParticipants int) uint {
+	return uint(turnNum % uint64(nParticipants))
+}
+
+// Pay moves amount from allocation of the mover of proposed state to allocation of the payee.
+func Pay(sp *protocol.StateProposal, payee uint, amount *big.Int) error {
+	if amount.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+
+	exit := sp.Outcome()
+	if len(exit) != 1 {
+		return ErrSingleAsset
+	}
+
+	allocations := exit[0].Allocations
+	mover := Mover(sp.TurnNum(), len(allocations))
+	if payee == mover || int(payee) >= len(allocations) {
+		return ErrInvalidPayee
+	}
+
+	if allocations[mover].Amount.Cmp(amount) < 0 {
+		return ErrInsufficientBalance
+	}
+
+	allocations[mover].Amount = new(big.Int).Sub(allocations[mover].Amount, amount)
+	allocations[payee].Amount = new(big.Int).Add(allocations[payee].Amount, amount)
+	sp.SetOutcome(exit)
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package payments
+
+import (
+	"app/pkg/nitro"
+	"app/pkg/protocol"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	"github.com/statechannels/go-nitro/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	payer = protocol.NewParticipant(common.HexToAddress("0xdd2fd4581271e230360230f9337d5c0430bf44c0"), types.Destination(common.HexToHash("0xdd2fd4581271e230360230f9337d5c0430bf44c0")), uint(0), big.NewInt(10))
+	payee = protocol.NewParticipant(common.HexToAddress("0x8626f6940e2eb28930efb4cef49b2d1f2c9c1199"), types.Destination(common.HexToHash("0x8626f6940e2eb28930efb4cef49b2d1f2c9c1199")), uint(1), big.NewInt(0))
+	keys  = [][]byte{
+		common.Hex2Bytes("de9be858da4a475276426320d5e9262ecfc3ba460bfac56360bfa6c4c28b4ee0"),
+		common.Hex2Bytes("df57089febbacf7ba0bc227dafbffa9fc08a93fdc68e1e42411a14efcf23656e"),
+	}
+)
+
+func getExit(amounts ...int64) outcome.Exit {
+	destinations := []types.Destination{payer.Destination, payee.Destination}
+	var allocations outcome.Allocations
+	for i, amount := range amounts {
+		allocations = append(allocations, outcome.Allocation{Destination: destinations[i], Amount: big.NewInt(amount)})
+	}
+
+	return outcome.Exit{{Asset: common.HexToAddress("0x"), Allocations: allocations}}
+}
+
+func getChannel(t *testing.T) *protocol.Channel {
+	contract := protocol.NewContract(nitro.Client{ChainID: big.NewInt(2)}, common.HexToAddress("0x"))
+	proposal := protocol.NewInitProposal(payer, contract)
+	require.NoError(t, proposal.AddParticipant(payee))
+	proposal.SetApp(common.HexToAddress("0x0a"), Codec{})
+
+	ch, err := protocol.InitChannel(proposal, 0)
+	assert.NoError(t, err)
+
+	for _, key := range keys {
+		_, err := ch.ApproveInitChannel(key)
+		assert.NoError(t, err)
+	}
+	for _, key := range keys {
+		_, err := ch.ApproveChannelFunding(key)
+		assert.NoError(t, err)
+	}
+
+	return ch
+}
+
+func TestValidTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     outcome.Exit
+		turnNumB uint64
+		err      error
+	}{
+		{"mover pays", getExit(10, 0), getExit(7, 3), 2, nil},
+		{"mover doesn't pay", getExit(10, 0), getExit(10, 0), 3, nil},
+		{"nonmover balance decreased", getExit(7, 3), getExit(8, 2), 2, ErrNonMoverDecreased},
+		{"total changed", getExit(10, 0), getExit(9, 0), 2, ErrTotalChanged},
+		{"allocations count", getExit(10, 0), getExit(10), 2, ErrAllocationsCount},
+		{"multiple assets", getExit(10, 0), append(getExit(10, 0), getExit(0, 0)...), 2, ErrSingleAsset},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidTransition(test.a, test.b, test.turnNumB, 2)
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, test.err)
+			}
+		})
+	}
+
+	t.Run("destination changed", func(t *testing.T) {
+		b := getExit(7, 3)
+		b[0].Allocations[1].Destination = types.Destination(common.HexToHash("0x01"))
+		assert.ErrorIs(t, ValidTransition(getExit(10, 0), b, 2, 2), ErrDestinationChanged)
+	})
+
+	t.Run("not a simple allocation", func(t *testing.T) {
+		b := getExit(7, 3)
+		b[0].Allocations[1].AllocationType = outcome.GuaranteeAllocationType
+		assert.ErrorIs(t, ValidTransition(getExit(10, 0), b, 2, 2), ErrNotSimpleAllocation)
+	})
+}
+
+func TestPay(t *testing.T) {
+	ch := getChannel(t)
+
+	sp, err := ch.ProposeState()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), Mover(sp.TurnNum(), 2))
+
+	assert.ErrorIs(t, Pay(sp, 1, big.NewInt(0)), ErrInvalidAmount)
+	assert.ErrorIs(t, Pay(sp, 0, big.NewInt(1)), ErrInvalidPayee)
+	assert.ErrorIs(t, Pay(sp, 1, big.NewInt(11)), ErrInsufficientBalance)
+
+	assert.NoError(t, Pay(sp, 1, big.NewInt(4)))
+	assert.Equal(t, getExit(6, 4)[0].Allocations, sp.Outcome()[0].Allocations)
+	for _, key := range keys {
+		_, err := ch.SignState(context.Background(), sp, key)
+		assert.NoError(t, err)
+	}
+
+	t.Run("payee can't be paid by nonmover", func(t *testing.T) {
+		sp, err := ch.ProposeState()
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), Mover(sp.TurnNum(), 2))
+
+		exit := sp.Outcome()
+		exit[0].Allocations[0].Amount = big.NewInt(5)
+		exit[0].Allocations[1].Amount = big.NewInt(5)
+		sp.SetOutcome(exit)
+
+		_, err = ch.SignState(context.Background(), sp, keys[1])
+		assert.ErrorIs(t, err, protocol.ErrInvalidTransition)
+	})
+}
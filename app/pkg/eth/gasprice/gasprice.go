@@ -8,16 +8,23 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
+// DefaultTimeout bounds gas price request if the context has no deadline.
+const DefaultTimeout = 30 * time.Second
+
 // Station represents information about gas price, gas limit.
 type Station struct {
 	GasPrice *big.Int
 	GasLimit uint64
 }
 
-// Calculate calculates gas price.
-func Calculate(ethClient ethclient.Client) (*big.Int, error) {
-	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(3*time.Second))
-	defer cancel()
+// Calculate calculates gas price, the request is bound to the context deadline
+// or to DefaultTimeout if the context has no deadline.
+func Calculate(ctx context.Context, ethClient ethclient.Client) (*big.Int, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultTimeout)
+		defer cancel()
+	}
 
 	gasPrice, err := ethClient.SuggestGasPrice(ctx)
 	if err != nil {
@@ -1,12 +1,14 @@
 package nitro
 
 import (
+	"context"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
 )
 
 // StateChannelContract represents available functions from Nitro protocol
@@ -21,22 +23,39 @@ type StateChannelContract interface {
 		Fingerprint   *big.Int
 	}, error)
 	Checkpoint(opts *bind.TransactOpts, fixedPart IForceMoveFixedPart, largestTurnNum *big.Int, variableParts []IForceMoveAppVariablePart, isFinalCount uint8, sigs []IForceMoveSignature, whoSignedWhat []uint8) (*types.Transaction, error)
+	Conclude(opts *bind.TransactOpts, largestTurnNum *big.Int, fixedPart IForceMoveFixedPart, appData []byte, outcome []byte, numStates uint8, whoSignedWhat []uint8, sigs []IForceMoveSignature) (*types.Transaction, error)
 	ConcludeAndTransferAllAssets(opts *bind.TransactOpts, largestTurnNum *big.Int, fixedPart IForceMoveFixedPart, appData []byte, outcomeBytes []byte, numStates uint8, whoSignedWhat []uint8, sigs []IForceMoveSignature) (*types.Transaction, error)
 	GetChainID(opts *bind.CallOpts) (*big.Int, error)
 	Holdings(opts *bind.CallOpts, arg0 common.Address, arg1 [32]byte) (*big.Int, error)
 }
 
+// HeaderReader reads block headers, it is implemented by ethclient.Client and simulated backend.
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// EventWatcher subscribes to adjudicator events, it is implemented by NitroAdjudicator binding.
+type EventWatcher interface {
+	WatchDeposited(opts *bind.WatchOpts, sink chan<- *NitroAdjudicatorDeposited, destination [][32]byte) (event.Subscription, error)
+	WatchChallengeRegistered(opts *bind.WatchOpts, sink chan<- *NitroAdjudicatorChallengeRegistered, channelId [][32]byte) (event.Subscription, error)
+	WatchConcluded(opts *bind.WatchOpts, sink chan<- *NitroAdjudicatorConcluded, channelId [][32]byte) (event.Subscription, error)
+}
+
 // Client stores information about adjudicator and chainID
 type Client struct {
 	Adjudicator StateChannelContract
 	ChainID     *big.Int
 	Eth         ethclient.Client
+	// Chain is used to read the latest block, e.g. its timestamp.
+	Chain HeaderReader
+	// Events is used to watch adjudicator events of channels.
+	Events EventWatcher
 }
 
 // NewClient returns a new Client from supplied params.
-func NewClient(contractAddr, rpcUrl string) (Client, error) {
+func NewClient(ctx context.Context, contractAddr, rpcUrl string) (Client, error) {
 	contractAddress := common.HexToAddress(contractAddr)
-	ethClient, err := ethclient.Dial(rpcUrl)
+	ethClient, err := ethclient.DialContext(ctx, rpcUrl)
 	if err != nil {
 		return Client{}, err
 	}
@@ -46,7 +65,7 @@ func NewClient(contractAddr, rpcUrl string) (Client, error) {
 		return Client{}, err
 	}
 
-	chainID, err := adjudicator.GetChainID(nil)
+	chainID, err := adjudicator.GetChainID(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return Client{}, err
 	}
@@ -55,5 +74,7 @@ func NewClient(contractAddr, rpcUrl string) (Client, error) {
 		Adjudicator: adjudicator,
 		Eth:         *ethClient,
 		ChainID:     chainID,
+		Chain:       ethClient,
+		Events:      adjudicator,
 	}, nil
 }
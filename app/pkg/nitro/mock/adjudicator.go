@@ -0,0 +1,944 @@
+package mock
+
+import (
+	"app/pkg/nitro"
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	nc "github.com/statechannels/go-nitro/crypto"
+)
+
+var (
+	ErrIncorrectChainID          = errors.New("mock: incorrect chainId")
+	ErrDepositToExternal         = errors.New("mock: deposit to external destination")
+	ErrHoldingsTooLow            = errors.New("mock: holdings < expectedHeld")
+	ErrHoldingsSufficient        = errors.New("mock: holdings already sufficient")
+	ErrIncorrectValue            = errors.New("mock: incorrect msg.value for deposit")
+	ErrInvalidInput              = errors.New("mock: insufficient or excess states, signatures or whoSignedWhat")
+	ErrLargestTurnNumTooLow      = errors.New("mock: largestTurnNum too low")
+	ErrUnacceptableWhoSignedWhat = errors.New("mock: unacceptable whoSignedWhat array")
+	ErrInvalidSignatures         = errors.New("mock: invalid signatures")
+	ErrChannelFinalized          = errors.New("mock: channel finalized")
+	ErrChannelNotFinalized       = errors.New("mock: channel not finalized")
+	ErrTurnNumRecordDecreased    = errors.New("mock: turnNumRecord decreased")
+	ErrTurnNumRecordNotIncreased = errors.New("mock: turnNumRecord not increased")
+	ErrChallengerNotParticipant  = errors.New("mock: challenger is not a participant")
+	ErrIncorrectFingerprint      = errors.New("mock: incorrect fingerprint")
+	ErrOutcomeChange             = errors.New("mock: outcome change forbidden")
+	ErrAppDataChange             = errors.New("mock: appData change forbidden")
+	ErrIsFinalRetrograde         = errors.New("mock: isFinal retrograde")
+	ErrUnknownApp                = errors.New("mock: app definition is not registered")
+	ErrInvalidAppTransition      = errors.New("mock: invalid ForceMoveApp transition")
+	ErrIndicesNotSorted          = errors.New("mock: indices must be sorted")
+	ErrTransferGuarantee         = errors.New("mock: cannot transfer a guarantee")
+)
+
+// App validates application specific transition, it mirrors IForceMoveApp.validTransition.
+type App func(a, b nitro.IForceMoveAppVariablePart, turnNumB, nParticipants uint64) bool
+
+// TrivialApp accepts every transition like TrivialApp contract.
+func TrivialApp(a, b nitro.IForceMoveAppVariablePart, turnNumB, nParticipants uint64) bool {
+	return true
+}
+
+// status represents channel status stored by the adjudicator.
+type status struct {
+	turnNumRecord uint64
+	finalizesAt   uint64
+	fingerprint   *big.Int
+}
+
+// emitted wraps emitted event, the feed requires events of a single type.
+type emitted struct {
+	event interface{}
+}
+
+// Adjudicator is an in-memory implementation of nitro.StateChannelContract.
+// It models NitroAdjudicator checks and effects: holdings, turn number records, finalization times,
+// payouts and events. Transactions aren't mined, effects are applied when transaction is returned.
+type Adjudicator struct {
+	Address common.Address
+	ChainID *big.Int
+
+	mu       sync.Mutex
+	now      uint64
+	nonce    uint64
+	holdings map[common.Address]map[[32]byte]*big.Int
+	statuses map[[32]byte]status
+	payouts  map[common.Address]map[common.Address]*big.Int
+	apps     map[common.Address]App
+	events   []interface{}
+	feed     event.Feed
+}
+
+var (
+	_ nitro.StateChannelContract = (*Adjudicator)(nil)
+	_ nitro.HeaderReader         = (*Adjudicator)(nil)
+	_ nitro.EventWatcher         = (*Adjudicator)(nil)
+)
+
+// NewAdjudicator returns a new Adjudicator of the chain, block timestamp is set to current time.
+func NewAdjudicator(chainID *big.Int) *Adjudicator {
+	return &Adjudicator{
+		Address:  common.HexToAddress("0xadadadadadadadadadadadadadadadadadadadad"),
+		ChainID:  chainID,
+		now:      uint64(time.Now().Unix()),
+		holdings: make(map[common.Address]map[[32]byte]*big.Int),
+		statuses: make(map[[32]byte]status),
+		payouts:  make(map[common.Address]map[common.Address]*big.Int),
+		apps:     make(map[common.Address]App),
+	}
+}
+
+// RegisterApp registers app rules deployed at app definition address.
+func (a *Adjudicator) RegisterApp(appDefinition common.Address, app App) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.apps[appDefinition] = app
+}
+
+// Time returns timestamp of the latest block.
+func (a *Adjudicator) Time() uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.now
+}
+
+// IncreaseTime moves block timestamp forward, e.g. to let challenge expire.
+func (a *Adjudicator) IncreaseTime(d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.now += uint64(d / time.Second)
+}
+
+// HeaderByNumber returns header of the latest block, block number is the number of sent transactions.
+func (a *Adjudicator) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := contextErr(ctx); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return &types.Header{Number: new(big.Int).SetUint64(a.nonce), Time: a.now}, nil
+}
+
+// Events returns emitted events in order, events are NitroAdjudicator binding event types.
+func (a *Adjudicator) Events() []interface{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return append([]interface{}{}, a.events...)
+}
+
+// Payout returns total amount of the asset paid out to external destination.
+func (a *Adjudicator) Payout(asset, destination common.Address) *big.Int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if amount, found := a.payouts[asset][destination]; found {
+		return new(big.Int).Set(amount)
+	}
+
+	return big.NewInt(0)
+}
+
+// Holdings returns amount of the asset held against channel.
+func (a *Adjudicator) Holdings(opts *bind.CallOpts, asset common.Address, channelId [32]byte) (*big.Int, error) {
+	if err := callErr(opts); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return new(big.Int).Set(a.holding(asset, channelId)), nil
+}
+
+// GetChainID returns chain id of the adjudicator.
+func (a *Adjudicator) GetChainID(opts *bind.CallOpts) (*big.Int, error) {
+	if err := callErr(opts); err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).Set(a.ChainID), nil
+}
+
+// UnpackStatus returns turn number record, finalization time and fingerprint of the channel.
+func (a *Adjudicator) UnpackStatus(opts *bind.CallOpts, channelId [32]byte) (struct {
+	TurnNumRecord *big.Int
+	FinalizesAt   *big.Int
+	Fingerprint   *big.Int
+}, error) {
+	if err := callErr(opts); err != nil {
+		return struct {
+			TurnNumRecord *big.Int
+			FinalizesAt   *big.Int
+			Fingerprint   *big.Int
+		}{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s := a.status(channelId)
+	return struct {
+		TurnNumRecord *big.Int
+		FinalizesAt   *big.Int
+		Fingerprint   *big.Int
+	}{
+		TurnNumRecord: new(big.Int).SetUint64(s.turnNumRecord),
+		FinalizesAt:   new(big.Int).SetUint64(s.finalizesAt),
+		Fingerprint:   new(big.Int).Set(s.fingerprint),
+	}, nil
+}
+
+// ValidTransition returns true if b is a valid transition from a, otherwise an error is returned.
+func (a *Adjudicator) ValidTransition(opts *bind.CallOpts, nParticipants *big.Int, isFinalAB [2]bool, ab [2]nitro.IForceMoveAppVariablePart, turnNumB *big.Int, appDefinition common.Address) (bool, error) {
+	if err := callErr(opts); err != nil {
+		return false, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.validTransition(nParticipants.Uint64(), isFinalAB, ab, turnNumB.Uint64(), appDefinition); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Deposit increases holdings of the channel up to expected held plus amount.
+func (a *Adjudicator) Deposit(opts *bind.TransactOpts, asset common.Address, channelId [32]byte, expectedHeld *big.Int, amount *big.Int) (*types.Transaction, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if isExternal(channelId) {
+		return nil, ErrDepositToExternal
+	}
+
+	held := a.holding(asset, channelId)
+	if held.Cmp(expectedHeld) < 0 {
+		return nil, ErrHoldingsTooLow
+	}
+	target := new(big.Int).Add(expectedHeld, amount)
+	if held.Cmp(target) >= 0 {
+		return nil, ErrHoldingsSufficient
+	}
+	if asset == (common.Address{}) && (opts.Value == nil || opts.Value.Cmp(amount) != 0) {
+		return nil, ErrIncorrectValue
+	}
+
+	tx, err := a.transaction(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	a.setHolding(asset, channelId, target)
+	a.emit(&nitro.NitroAdjudicatorDeposited{
+		Destination:         channelId,
+		Asset:               asset,
+		AmountDeposited:     new(big.Int).Sub(target, held),
+		DestinationHoldings: new(big.Int).Set(target),
+	})
+
+	return tx, nil
+}
+
+// Checkpoint records supported state turn number and clears ongoing challenge.
+func (a *Adjudicator) Checkpoint(opts *bind.TransactOpts, fixedPart nitro.IForceMoveFixedPart, largestTurnNum *big.Int, variableParts []nitro.IForceMoveAppVariablePart, isFinalCount uint8, sigs []nitro.IForceMoveSignature, whoSignedWhat []uint8) (*types.Transaction, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := validInput(len(fixedPart.Participants), len(variableParts), len(sigs), len(whoSignedWhat)); err != nil {
+		return nil, err
+	}
+
+	channelID, err := a.channelID(fixedPart)
+	if err != nil {
+		return nil, err
+	}
+	if a.finalized(channelID) {
+		return nil, ErrChannelFinalized
+	}
+	if largestTurnNum.Uint64() <= a.status(channelID).turnNumRecord {
+		return nil, ErrTurnNumRecordNotIncreased
+	}
+	if _, err := a.stateSupportedBy(largestTurnNum.Uint64(), variableParts, isFinalCount, fixedPart, sigs, whoSignedWhat); err != nil {
+		return nil, err
+	}
+
+	tx, err := a.transaction(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	a.statuses[channelID] = status{turnNumRecord: largestTurnNum.Uint64(), fingerprint: big.NewInt(0)}
+	a.emit(&nitro.NitroAdjudicatorChallengeCleared{ChannelId: channelID, NewTurnNumRecord: new(big.Int).Set(largestTurnNum)})
+
+	return tx, nil
+}
+
+// Challenge registers challenge with supported state, channel finalizes after challenge duration.
+func (a *Adjudicator) Challenge(opts *bind.TransactOpts, fixedPart nitro.IForceMoveFixedPart, largestTurnNum *big.Int, variableParts []nitro.IForceMoveAppVariablePart, isFinalCount uint8, sigs []nitro.IForceMoveSignature, whoSignedWhat []uint8, challengerSig nitro.IForceMoveSignature) (*types.Transaction, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := validInput(len(fixedPart.Participants), len(variableParts), len(sigs), len(whoSignedWhat)); err != nil {
+		return nil, err
+	}
+
+	channelID, err := a.channelID(fixedPart)
+	if err != nil {
+		return nil, err
+	}
+
+	current := a.status(channelID)
+	switch {
+	case a.finalized(channelID):
+		return nil, ErrChannelFinalized
+	case current.finalizesAt == 0 && largestTurnNum.Uint64() < current.turnNumRecord:
+		return nil, ErrTurnNumRecordDecreased
+	case current.finalizesAt != 0 && largestTurnNum.Uint64() <= current.turnNumRecord:
+		return nil, ErrTurnNumRecordNotIncreased
+	}
+
+	supportedStateHash, err := a.stateSupportedBy(largestTurnNum.Uint64(), variableParts, isFinalCount, fixedPart, sigs, whoSignedWhat)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := abi.Arguments{{Type: bytes32Type}, {Type: stringType}}.Pack(supportedStateHash, "forceMove")
+	if err != nil {
+		return nil, err
+	}
+	challenger, err := recoverSigner(crypto.Keccak256(message), challengerSig)
+	if err != nil || !contains(fixedPart.Participants, challenger) {
+		return nil, ErrChallengerNotParticipant
+	}
+
+	tx, err := a.transaction(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	finalizesAt := a.now + fixedPart.ChallengeDuration.Uint64()
+	outcomeHash := crypto.Keccak256Hash(variableParts[len(variableParts)-1].Outcome)
+	a.statuses[channelID] = status{
+		turnNumRecord: largestTurnNum.Uint64(),
+		finalizesAt:   finalizesAt,
+		fingerprint:   nitro.Fingerprint(supportedStateHash, outcomeHash),
+	}
+	a.emit(&nitro.NitroAdjudicatorChallengeRegistered{
+		ChannelId:     channelID,
+		TurnNumRecord: new(big.Int).Set(largestTurnNum),
+		FinalizesAt:   new(big.Int).SetUint64(finalizesAt),
+		IsFinal:       isFinalCount > 0,
+		FixedPart:     fixedPart,
+		VariableParts: variableParts,
+		Sigs:          sigs,
+		WhoSignedWhat: whoSignedWhat,
+	})
+
+	return tx, nil
+}
+
+// Conclude finalizes channel with final state signed by all participants.
+func (a *Adjudicator) Conclude(opts *bind.TransactOpts, largestTurnNum *big.Int, fixedPart nitro.IForceMoveFixedPart, appData []byte, outcomeBytes []byte, numStates uint8, whoSignedWhat []uint8, sigs []nitro.IForceMoveSignature) (*types.Transaction, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	channelID, err := a.conclude(largestTurnNum.Uint64(), fixedPart, appData, outcomeBytes, numStates, whoSignedWhat, sigs)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := a.transaction(opts)
+	if err != nil {
+		return nil, err
+	}
+	a.applyConclude(channelID, outcomeBytes)
+
+	return tx, nil
+}
+
+// ConcludeAndTransferAllAssets finalizes channel with final state signed by all participants and pays out all assets.
+func (a *Adjudicator) ConcludeAndTransferAllAssets(opts *bind.TransactOpts, largestTurnNum *big.Int, fixedPart nitro.IForceMoveFixedPart, appData []byte, outcomeBytes []byte, numStates uint8, whoSignedWhat []uint8, sigs []nitro.IForceMoveSignature) (*types.Transaction, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	channelID, err := a.conclude(largestTurnNum.Uint64(), fixedPart, appData, outcomeBytes, numStates, whoSignedWhat, sigs)
+	if err != nil {
+		return nil, err
+	}
+
+	exit, payouts, err := a.transferAllAssets(channelID, outcomeBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := a.transaction(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	a.applyConclude(channelID, outcomeBytes)
+	a.applyTransferAll(channelID, outcomeBytes, [32]byte{}, exit, payouts)
+
+	return tx, nil
+}
+
+// TransferAllAssets pays out all assets of the finalized channel.
+func (a *Adjudicator) TransferAllAssets(opts *bind.TransactOpts, channelId [32]byte, outcomeBytes []byte, stateHash [32]byte) (*types.Transaction, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.finalized(channelId) {
+		return nil, ErrChannelNotFinalized
+	}
+	if a.status(channelId).fingerprint.Cmp(nitro.Fingerprint(stateHash, crypto.Keccak256Hash(outcomeBytes))) != 0 {
+		return nil, ErrIncorrectFingerprint
+	}
+
+	exit, payouts, err := a.transferAllAssets(channelId, outcomeBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := a.transaction(opts)
+	if err != nil {
+		return nil, err
+	}
+	a.applyTransferAll(channelId, outcomeBytes, stateHash, exit, payouts)
+
+	return tx, nil
+}
+
+// Transfer pays out allocations of single asset at indices, all allocations are paid out when indices are empty.
+func (a *Adjudicator) Transfer(opts *bind.TransactOpts, assetIndex *big.Int, fromChannelId [32]byte, outcomeBytes []byte, stateHash [32]byte, indices []*big.Int) (*types.Transaction, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := 0; i+1 < len(indices); i++ {
+		if indices[i].Cmp(indices[i+1]) >= 0 {
+			return nil, ErrIndicesNotSorted
+		}
+	}
+	if !a.finalized(fromChannelId) {
+		return nil, ErrChannelNotFinalized
+	}
+	if a.status(fromChannelId).fingerprint.Cmp(nitro.Fingerprint(stateHash, crypto.Keccak256Hash(outcomeBytes))) != 0 {
+		return nil, ErrIncorrectFingerprint
+	}
+
+	exit, err := outcome.Decode(outcomeBytes)
+	if err != nil {
+		return nil, err
+	}
+	index := int(assetIndex.Int64())
+	if index >= len(exit) {
+		return nil, ErrInvalidInput
+	}
+
+	asset := exit[index].Asset
+	initialHoldings := new(big.Int).Set(a.holding(asset, fromChannelId))
+	allocations, exitAllocations, total, err := computeTransfer(initialHoldings, exit[index].Allocations, indices)
+	if err != nil {
+		return nil, err
+	}
+
+	exit[index].Allocations = allocations
+	newOutcome, err := exit.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := a.transaction(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	a.setHolding(asset, fromChannelId, new(big.Int).Sub(initialHoldings, total))
+	current := a.status(fromChannelId)
+	current.fingerprint = nitro.Fingerprint(stateHash, crypto.Keccak256Hash(newOutcome))
+	a.statuses[fromChannelId] = current
+	a.emit(&nitro.NitroAdjudicatorAllocationUpdated{ChannelId: fromChannelId, AssetIndex: new(big.Int).Set(assetIndex), InitialHoldings: initialHoldings})
+	a.executeExit(asset, exitAllocations)
+
+	return tx, nil
+}
+
+// conclude mirrors checks of ForceMove._conclude and returns id of the channel.
+func (a *Adjudicator) conclude(largestTurnNum uint64, fixedPart nitro.IForceMoveFixedPart, appData []byte, outcomeBytes []byte, numStates uint8, whoSignedWhat []uint8, sigs []nitro.IForceMoveSignature) ([32]byte, error) {
+	channelID, err := a.channelID(fixedPart)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if a.finalized(channelID) {
+		return [32]byte{}, ErrChannelFinalized
+	}
+	if err := validInput(len(fixedPart.Participants), int(numStates), len(sigs), len(whoSignedWhat)); err != nil {
+		return [32]byte{}, err
+	}
+	if largestTurnNum+1 < uint64(numStates) {
+		return [32]byte{}, ErrLargestTurnNumTooLow
+	}
+
+	stateHashes := make([][32]byte, numStates)
+	for i := range stateHashes {
+		turnNum := largestTurnNum + uint64(i+1) - uint64(numStates)
+		stateHashes[i], err = hashState(fixedPart, nitro.IForceMoveAppVariablePart{Outcome: outcomeBytes, AppData: appData}, turnNum, true)
+		if err != nil {
+			return [32]byte{}, err
+		}
+	}
+	if err := validSignatures(largestTurnNum, fixedPart.Participants, stateHashes, sigs, whoSignedWhat); err != nil {
+		return [32]byte{}, err
+	}
+
+	return channelID, nil
+}
+
+// applyConclude finalizes channel at the current block, zero state hash is registered with the outcome.
+func (a *Adjudicator) applyConclude(channelID [32]byte, outcomeBytes []byte) {
+	a.statuses[channelID] = status{finalizesAt: a.now, fingerprint: nitro.Fingerprint([32]byte{}, crypto.Keccak256Hash(outcomeBytes))}
+	a.emit(&nitro.NitroAdjudicatorConcluded{ChannelId: channelID, FinalizesAt: new(big.Int).SetUint64(a.now)})
+}
+
+// transferAllAssets computes allocations left in the channel and payouts of every asset.
+func (a *Adjudicator) transferAllAssets(channelID [32]byte, outcomeBytes []byte) (outcome.Exit, []outcome.Allocations, error) {
+	exit, err := outcome.Decode(outcomeBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payouts := make([]outcome.Allocations, len(exit))
+	for i, singleAssetExit := range exit {
+		allocations, exitAllocations, _, err := computeTransfer(a.holding(singleAssetExit.Asset, channelID), singleAssetExit.Allocations, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		exit[i].Allocations = allocations
+		payouts[i] = exitAllocations
+	}
+
+	return exit, payouts, nil
+}
+
+// applyTransferAll applies effects of transferAllAssets, channel status is deleted when nothing is left to pay out.
+func (a *Adjudicator) applyTransferAll(channelID [32]byte, outcomeBytes []byte, stateHash [32]byte, exit outcome.Exit, payouts []outcome.Allocations) {
+	allocatesOnlyZeros := true
+	for i, singleAssetExit := range exit {
+		initialHoldings := new(big.Int).Set(a.holding(singleAssetExit.Asset, channelID))
+		a.setHolding(singleAssetExit.Asset, channelID, new(big.Int).Sub(initialHoldings, payouts[i].Total()))
+		a.emit(&nitro.NitroAdjudicatorAllocationUpdated{ChannelId: channelID, AssetIndex: big.NewInt(int64(i)), InitialHoldings: initialHoldings})
+
+		for _, allocation := range singleAssetExit.Allocations {
+			if allocation.Amount.Sign() != 0 {
+				allocatesOnlyZeros = false
+			}
+		}
+	}
+
+	if allocatesOnlyZeros {
+		delete(a.statuses, channelID)
+	} else {
+		// NitroAdjudicator hashes abi encoded outcome bytes here, fingerprint is kept the same way
+		encoded, _ := abi.Arguments{{Type: bytesType}}.Pack(outcomeBytes)
+		current := a.status(channelID)
+		current.fingerprint = nitro.Fingerprint(stateHash, crypto.Keccak256Hash(encoded))
+		a.statuses[channelID] = current
+	}
+
+	for i, singleAssetExit := range exit {
+		a.executeExit(singleAssetExit.Asset, payouts[i])
+	}
+}
+
+// executeExit pays out allocations to external destinations and credits holdings of channel destinations.
+func (a *Adjudicator) executeExit(asset common.Address, allocations outcome.Allocations) {
+	for _, allocation := range allocations {
+		if !isExternal(allocation.Destination) {
+			a.setHolding(asset, allocation.Destination, new(big.Int).Add(a.holding(asset, allocation.Destination), allocation.Amount))
+			continue
+		}
+
+		destination := common.BytesToAddress(allocation.Destination[12:])
+		if _, found := a.payouts[asset]; !found {
+			a.payouts[asset] = make(map[common.Address]*big.Int)
+		}
+		paid, found := a.payouts[asset][destination]
+		if !found {
+			paid = big.NewInt(0)
+		}
+		a.payouts[asset][destination] = new(big.Int).Add(paid, allocation.Amount)
+	}
+}
+
+// validTransition mirrors ForceMove._requireValidTransition.
+func (a *Adjudicator) validTransition(nParticipants uint64, isFinalAB [2]bool, ab [2]nitro.IForceMoveAppVariablePart, turnNumB uint64, appDefinition common.Address) error {
+	if isFinalAB[1] {
+		if !bytes.Equal(ab[1].Outcome, ab[0].Outcome) {
+			return ErrOutcomeChange
+		}
+		return nil
+	}
+
+	if isFinalAB[0] {
+		return ErrIsFinalRetrograde
+	}
+
+	if turnNumB < 2*nParticipants {
+		if !bytes.Equal(ab[1].Outcome, ab[0].Outcome) {
+			return ErrOutcomeChange
+		}
+		if !bytes.Equal(ab[1].AppData, ab[0].AppData) {
+			return ErrAppDataChange
+		}
+		return nil
+	}
+
+	app, found := a.apps[appDefinition]
+	if !found {
+		return ErrUnknownApp
+	}
+	if !app(ab[0], ab[1], turnNumB, nParticipants) {
+		return ErrInvalidAppTransition
+	}
+
+	return nil
+}
+
+// stateSupportedBy mirrors ForceMove._requireStateSupportedBy and returns hash of the supported state.
+func (a *Adjudicator) stateSupportedBy(largestTurnNum uint64, variableParts []nitro.IForceMoveAppVariablePart, isFinalCount uint8, fixedPart nitro.IForceMoveFixedPart, sigs []nitro.IForceMoveSignature, whoSignedWhat []uint8) ([32]byte, error) {
+	if largestTurnNum+1 < uint64(len(variableParts)) {
+		return [32]byte{}, ErrLargestTurnNumTooLow
+	}
+
+	firstFinalTurnNum := largestTurnNum - uint64(isFinalCount) + 1
+	stateHashes := make([][32]byte, len(variableParts))
+	for i := range variableParts {
+		turnNum := largestTurnNum - uint64(len(variableParts)) + 1 + uint64(i)
+
+		var err error
+		stateHashes[i], err = hashState(fixedPart, variableParts[i], turnNum, turnNum >= firstFinalTurnNum)
+		if err != nil {
+			return [32]byte{}, err
+		}
+
+		if turnNum < largestTurnNum {
+			err := a.validTransition(
+				uint64(len(fixedPart.Participants)),
+				[2]bool{turnNum >= firstFinalTurnNum, turnNum+1 >= firstFinalTurnNum},
+				[2]nitro.IForceMoveAppVariablePart{variableParts[i], variableParts[i+1]},
+				turnNum+1,
+				fixedPart.AppDefinition,
+			)
+			if err != nil {
+				return [32]byte{}, err
+			}
+		}
+	}
+
+	if err := validSignatures(largestTurnNum, fixedPart.Participants, stateHashes, sigs, whoSignedWhat); err != nil {
+		return [32]byte{}, err
+	}
+
+	return stateHashes[len(stateHashes)-1], nil
+}
+
+// channelID returns id of the channel, an error is thrown if chain id doesn't match.
+func (a *Adjudicator) channelID(fixedPart nitro.IForceMoveFixedPart) ([32]byte, error) {
+	if fixedPart.ChainId == nil || fixedPart.ChainId.Cmp(a.ChainID) != 0 {
+		return [32]byte{}, ErrIncorrectChainID
+	}
+
+	return state.FixedPart{
+		ChainId:           fixedPart.ChainId,
+		Participants:      fixedPart.Participants,
+		ChannelNonce:      fixedPart.ChannelNonce,
+		AppDefinition:     fixedPart.AppDefinition,
+		ChallengeDuration: fixedPart.ChallengeDuration,
+	}.ChannelId()
+}
+
+// finalized returns true if channel finalization time has passed.
+func (a *Adjudicator) finalized(channelID [32]byte) bool {
+	return nitro.Mode(a.status(channelID).finalizesAt, a.now) == nitro.Finalized
+}
+
+// status returns status of the channel, zero status is returned for unknown channel.
+func (a *Adjudicator) status(channelID [32]byte) status {
+	s, found := a.statuses[channelID]
+	if !found {
+		return status{fingerprint: big.NewInt(0)}
+	}
+
+	return s
+}
+
+// holding returns holdings of the asset against destination.
+func (a *Adjudicator) holding(asset common.Address, destination [32]byte) *big.Int {
+	if amount, found := a.holdings[asset][destination]; found {
+		return amount
+	}
+
+	return big.NewInt(0)
+}
+
+// setHolding sets holdings of the asset against destination.
+func (a *Adjudicator) setHolding(asset common.Address, destination [32]byte, amount *big.Int) {
+	if _, found := a.holdings[asset]; !found {
+		a.holdings[asset] = make(map[[32]byte]*big.Int)
+	}
+	a.holdings[asset][destination] = amount
+}
+
+// emit appends event to the list of emitted events.
+func (a *Adjudicator) emit(event interface{}) {
+	a.events = append(a.events, event)
+	a.feed.Send(emitted{event})
+}
+
+// transaction returns a transaction sent to the adjudicator, it is signed when signer is supplied.
+// An error is returned if the transaction context is done.
+func (a *Adjudicator) transaction(opts *bind.TransactOpts) (*types.Transaction, error) {
+	if err := contextErr(opts.Context); err != nil {
+		return nil, err
+	}
+
+	a.nonce++
+
+	value := opts.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	tx := types.NewTx(&types.LegacyTx{Nonce: a.nonce, To: &a.Address, Value: value, Gas: opts.GasLimit, GasPrice: opts.GasPrice})
+	if opts.Signer == nil {
+		return tx, nil
+	}
+
+	return opts.Signer(opts.From, tx)
+}
+
+var (
+	bytes32Type, _ = abi.NewType("bytes32", "", nil)
+	bytesType, _   = abi.NewType("bytes", "", nil)
+	stringType, _  = abi.NewType("string", "", nil)
+)
+
+// hashState returns hash of the state built from fixed and variable parts.
+func hashState(fixedPart nitro.IForceMoveFixedPart, variablePart nitro.IForceMoveAppVariablePart, turnNum uint64, isFinal bool) ([32]byte, error) {
+	exit, err := outcome.Decode(variablePart.Outcome)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return state.State{
+		ChainId:           fixedPart.ChainId,
+		Participants:      fixedPart.Participants,
+		ChannelNonce:      fixedPart.ChannelNonce,
+		AppDefinition:     fixedPart.AppDefinition,
+		ChallengeDuration: fixedPart.ChallengeDuration,
+		AppData:           variablePart.AppData,
+		Outcome:           exit,
+		TurnNum:           turnNum,
+		IsFinal:           isFinal,
+	}.Hash()
+}
+
+// validInput mirrors ForceMove.requireValidInput.
+func validInput(numParticipants, numStates, numSigs, numWhoSignedWhats int) error {
+	if numParticipants < numStates || numStates == 0 || numSigs != numParticipants ||
+		numWhoSignedWhats != numParticipants || numParticipants > 255 {
+		return ErrInvalidInput
+	}
+
+	return nil
+}
+
+// validSignatures mirrors ForceMove._validSignatures.
+func validSignatures(largestTurnNum uint64, participants []common.Address, stateHashes [][32]byte, sigs []nitro.IForceMoveSignature, whoSignedWhat []uint8) error {
+	nParticipants := uint64(len(participants))
+	for i := range participants {
+		offset := (nParticipants + largestTurnNum - uint64(i)) % nParticipants
+		if uint64(whoSignedWhat[i])+offset+1 < uint64(len(stateHashes)) {
+			return ErrUnacceptableWhoSignedWhat
+		}
+	}
+
+	for i, participant := range participants {
+		if int(whoSignedWhat[i]) >= len(stateHashes) {
+			return ErrInvalidSignatures
+		}
+
+		signer, err := recoverSigner(stateHashes[whoSignedWhat[i]][:], sigs[i])
+		if err != nil || signer != participant {
+			return ErrInvalidSignatures
+		}
+	}
+
+	return nil
+}
+
+// recoverSigner returns signer of ethereum signed message.
+func recoverSigner(message []byte, sig nitro.IForceMoveSignature) (common.Address, error) {
+	return nc.RecoverEthereumMessageSigner(message, nc.Signature{R: sig.R[:], S: sig.S[:], V: sig.V})
+}
+
+// computeTransfer mirrors MultiAssetHolder.compute_transfer_effects_and_interactions.
+// It returns allocations left in the channel, paid out allocations and total payout.
+func computeTransfer(initialHoldings *big.Int, allocations outcome.Allocations, indices []*big.Int) (outcome.Allocations, outcome.Allocations, *big.Int, error) {
+	newAllocations := allocations.Clone()
+	var exitAllocations outcome.Allocations
+	total := big.NewInt(0)
+	surplus := new(big.Int).Set(initialHoldings)
+
+	k := 0
+	for i, allocation := range allocations {
+		affords := allocation.Amount
+		if surplus.Cmp(affords) < 0 {
+			affords = surplus
+		}
+		affords = new(big.Int).Set(affords)
+
+		if len(indices) == 0 || (k < len(indices) && indices[k].Int64() == int64(i)) {
+			if allocation.AllocationType == outcome.GuaranteeAllocationType {
+				return nil, nil, nil, ErrTransferGuarantee
+			}
+
+			newAllocations[i].Amount = new(big.Int).Sub(allocation.Amount, affords)
+			exit := allocation.Clone()
+			exit.Amount = affords
+			exitAllocations = append(exitAllocations, exit)
+			total.Add(total, affords)
+			k++
+		}
+
+		surplus.Sub(surplus, affords)
+	}
+
+	return newAllocations, exitAllocations, total, nil
+}
+
+// isExternal returns true if destination is an address padded with zeros.
+func isExternal(destination [32]byte) bool {
+	return bytes.Equal(destination[:12], make([]byte, 12))
+}
+
+// contains returns true if address is in the list.
+func contains(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WatchDeposited subscribes to Deposited events of the destinations, all destinations are watched when none is given.
+func (a *Adjudicator) WatchDeposited(opts *bind.WatchOpts, sink chan<- *nitro.NitroAdjudicatorDeposited, destination [][32]byte) (event.Subscription, error) {
+	return a.watch(func(e interface{}, quit <-chan struct{}) {
+		if deposited, ok := e.(*nitro.NitroAdjudicatorDeposited); ok && watched(destination, deposited.Destination) {
+			select {
+			case sink <- deposited:
+			case <-quit:
+			}
+		}
+	}), nil
+}
+
+// WatchChallengeRegistered subscribes to ChallengeRegistered events of the channels, all channels are watched when none is given.
+func (a *Adjudicator) WatchChallengeRegistered(opts *bind.WatchOpts, sink chan<- *nitro.NitroAdjudicatorChallengeRegistered, channelId [][32]byte) (event.Subscription, error) {
+	return a.watch(func(e interface{}, quit <-chan struct{}) {
+		if registered, ok := e.(*nitro.NitroAdjudicatorChallengeRegistered); ok && watched(channelId, registered.ChannelId) {
+			select {
+			case sink <- registered:
+			case <-quit:
+			}
+		}
+	}), nil
+}
+
+// WatchConcluded subscribes to Concluded events of the channels, all channels are watched when none is given.
+func (a *Adjudicator) WatchConcluded(opts *bind.WatchOpts, sink chan<- *nitro.NitroAdjudicatorConcluded, channelId [][32]byte) (event.Subscription, error) {
+	return a.watch(func(e interface{}, quit <-chan struct{}) {
+		if concluded, ok := e.(*nitro.NitroAdjudicatorConcluded); ok && watched(channelId, concluded.ChannelId) {
+			select {
+			case sink <- concluded:
+			case <-quit:
+			}
+		}
+	}), nil
+}
+
+// watch subscribes to emitted events, deliver sends the event to the sink if it is watched.
+// Events are buffered, so emitting doesn't wait for slow subscribers.
+func (a *Adjudicator) watch(deliver func(e interface{}, quit <-chan struct{})) event.Subscription {
+	events := make(chan emitted, 64)
+	sub := a.feed.Subscribe(events)
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case e := <-events:
+				deliver(e.event, quit)
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
+
+// watched returns true if id is in the list, every id is watched when the list is empty.
+func watched(ids [][32]byte, id [32]byte) bool {
+	if len(ids) == 0 {
+		return true
+	}
+
+	for _, watchedID := range ids {
+		if watchedID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// callErr returns error of the done call context.
+func callErr(opts *bind.CallOpts) error {
+	if opts == nil {
+		return nil
+	}
+
+	return contextErr(opts.Context)
+}
+
+// contextErr returns error of the done context, nil context is never done.
+func contextErr(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,345 @@
+package mock
+
+import (
+	"app/pkg/nitro"
+	"app/pkg/protocol"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/statechannels/go-nitro/channel/state"
+	nc "github.com/statechannels/go-nitro/crypto"
+	"github.com/statechannels/go-nitro/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var chainID = big.NewInt(1337)
+
+// testChannel is a channel between two participants with the mock adjudicator.
+type testChannel struct {
+	adjudicator  *Adjudicator
+	channel      *protocol.Channel
+	participants []*protocol.Participant
+	keys         [][]byte
+}
+
+// transferCodec is the codec of test channels which move funds directly in the outcome of non-final states.
+// App data is decoded as liabilities, but unlike protocol.LiabilitiesCodec outcome isn't checked against them.
+type transferCodec struct {
+	liabilities protocol.LiabilitiesCodec
+}
+
+func (c transferCodec) Decode(appData []byte) (interface{}, error) {
+	return c.liabilities.Decode(appData)
+}
+
+func (c transferCodec) Encode(app interface{}) ([]byte, error) {
+	return c.liabilities.Encode(app)
+}
+
+func (c transferCodec) ValidTransition(from, to interface{}) error {
+	return c.liabilities.ValidTransition(from, to)
+}
+
+func getTestChannel(t *testing.T, lockedAmounts ...int64) testChannel {
+	tc := testChannel{adjudicator: NewAdjudicator(chainID)}
+	for i, amount := range lockedAmounts {
+		key, address := nc.GeneratePrivateKeyAndAddress()
+		tc.participants = append(tc.participants, protocol.NewParticipant(address, types.AddressToDestination(address), uint(i), big.NewInt(amount)))
+		tc.keys = append(tc.keys, key)
+	}
+
+	contract := protocol.NewContract(nitro.Client{Adjudicator: tc.adjudicator, ChainID: chainID}, common.Address{})
+	proposal := protocol.NewInitProposal(tc.participants[0], contract)
+	for _, p := range tc.participants[1:] {
+		require.NoError(t, proposal.AddParticipant(p))
+	}
+	proposal.Codec = transferCodec{}
+
+	ch, err := protocol.InitChannel(proposal, 0)
+	require.NoError(t, err)
+	for _, key := range tc.keys {
+		_, err := ch.ApproveInitChannel(key)
+		require.NoError(t, err)
+	}
+	tc.channel = ch
+
+	return tc
+}
+
+// fund deposits locked amounts of all participants and signs postfund state.
+func (tc testChannel) fund(t *testing.T) {
+	for i, p := range tc.participants {
+		_, err := tc.channel.FundChannel(context.Background(), p, tc.keys[i])
+		require.NoError(t, err)
+	}
+
+	for _, key := range tc.keys {
+		_, err := tc.channel.ApproveChannelFunding(key)
+		require.NoError(t, err)
+	}
+}
+
+// trade moves amount from the first to the second participant and returns signatures of the new state.
+func (tc testChannel) trade(t *testing.T, amount int64, final bool) map[common.Address]state.Signature {
+	sp, err := tc.channel.ProposeState()
+	require.NoError(t, err)
+
+	exit := sp.Outcome()
+	exit[0].Allocations[0].Amount = new(big.Int).Sub(exit[0].Allocations[0].Amount, big.NewInt(amount))
+	exit[0].Allocations[1].Amount = new(big.Int).Add(exit[0].Allocations[1].Amount, big.NewInt(amount))
+	sp.SetOutcome(exit)
+	if final {
+		require.NoError(t, sp.SetFinal())
+	}
+
+	signatures := make(map[common.Address]state.Signature)
+	for i, key := range tc.keys {
+		signature, err := tc.channel.SignState(context.Background(), sp, key)
+		require.NoError(t, err)
+		signatures[tc.participants[i].Address] = signature
+	}
+
+	return signatures
+}
+
+// challenge registers challenge with the current state signed by all participants.
+func (tc testChannel) challenge(t *testing.T, signatures map[common.Address]state.Signature) error {
+	s := tc.channel.CurrentState()
+	outcomeBytes, err := s.Outcome.Encode()
+	require.NoError(t, err)
+	stateHash, err := s.Hash()
+	require.NoError(t, err)
+
+	var sigs []nitro.IForceMoveSignature
+	for _, address := range s.Participants {
+		sigs = append(sigs, forceMoveSignature(signatures[address]))
+	}
+
+	message, err := abi.Arguments{{Type: bytes32Type}, {Type: stringType}}.Pack(stateHash, "forceMove")
+	require.NoError(t, err)
+	challengerSig, err := nc.SignEthereumMessage(crypto.Keccak256(message), tc.keys[0])
+	require.NoError(t, err)
+
+	_, err = tc.adjudicator.Challenge(&bind.TransactOpts{},
+		fixedPart(s),
+		big.NewInt(int64(s.TurnNum)),
+		[]nitro.IForceMoveAppVariablePart{{Outcome: outcomeBytes, AppData: s.AppData}},
+		0,
+		sigs,
+		make([]uint8, len(s.Participants)),
+		forceMoveSignature(challengerSig),
+	)
+
+	return err
+}
+
+func fixedPart(s state.State) nitro.IForceMoveFixedPart {
+	return nitro.IForceMoveFixedPart{
+		ChainId:           s.ChainId,
+		Participants:      s.Participants,
+		ChannelNonce:      s.ChannelNonce,
+		AppDefinition:     s.AppDefinition,
+		ChallengeDuration: s.ChallengeDuration,
+	}
+}
+
+func forceMoveSignature(signature state.Signature) nitro.IForceMoveSignature {
+	var sig nitro.IForceMoveSignature
+	copy(sig.R[:], signature.R)
+	copy(sig.S[:], signature.S)
+	sig.V = signature.V
+
+	return sig
+}
+
+func TestDeposit(t *testing.T) {
+	tc := getTestChannel(t, 5, 3)
+	channelID := tc.channel.ID()
+
+	t.Run("holdings are lower than expected", func(t *testing.T) {
+		_, err := tc.adjudicator.Deposit(&bind.TransactOpts{Value: big.NewInt(5)}, common.Address{}, channelID, big.NewInt(1), big.NewInt(5))
+		assert.ErrorIs(t, err, ErrHoldingsTooLow)
+	})
+
+	t.Run("incorrect value", func(t *testing.T) {
+		_, err := tc.adjudicator.Deposit(&bind.TransactOpts{Value: big.NewInt(4)}, common.Address{}, channelID, big.NewInt(0), big.NewInt(5))
+		assert.ErrorIs(t, err, ErrIncorrectValue)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := tc.adjudicator.Deposit(&bind.TransactOpts{Value: big.NewInt(5), Context: ctx}, common.Address{}, channelID, big.NewInt(0), big.NewInt(5))
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = tc.adjudicator.Holdings(&bind.CallOpts{Context: ctx}, common.Address{}, channelID)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("deposit to external destination", func(t *testing.T) {
+		_, err := tc.adjudicator.Deposit(&bind.TransactOpts{Value: big.NewInt(5)}, common.Address{}, types.AddressToDestination(tc.participants[0].Address), big.NewInt(0), big.NewInt(5))
+		assert.ErrorIs(t, err, ErrDepositToExternal)
+	})
+
+	tc.fund(t)
+	holdings, err := tc.channel.CheckHoldings(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(8), holdings)
+
+	t.Run("holdings already sufficient", func(t *testing.T) {
+		_, err := tc.adjudicator.Deposit(&bind.TransactOpts{Value: big.NewInt(3)}, common.Address{}, channelID, big.NewInt(5), big.NewInt(3))
+		assert.ErrorIs(t, err, ErrHoldingsSufficient)
+	})
+
+	events := tc.adjudicator.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, &nitro.NitroAdjudicatorDeposited{Destination: channelID, Asset: common.Address{}, AmountDeposited: big.NewInt(3), DestinationHoldings: big.NewInt(8)}, events[1])
+}
+
+func TestConclude(t *testing.T) {
+	t.Run("conclude with final state", func(t *testing.T) {
+		tc := getTestChannel(t, 5, 3)
+		tc.fund(t)
+		tc.trade(t, 1, false)
+		signatures := tc.trade(t, 1, true)
+
+		_, err := tc.channel.Conclude(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		require.NoError(t, err)
+
+		assert.Equal(t, big.NewInt(3), tc.adjudicator.Payout(common.Address{}, tc.participants[0].Address))
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+		holdings, err := tc.channel.CheckHoldings(context.Background())
+		assert.NoError(t, err)
+		assert.Zero(t, holdings.Sign())
+
+		// status of the drained channel is deleted, concluding it again pays out nothing
+		_, err = tc.channel.Conclude(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		tc := getTestChannel(t, 5, 3)
+		tc.fund(t)
+		signatures := tc.trade(t, 1, true)
+		delete(signatures, tc.participants[1].Address)
+
+		_, err := tc.channel.Conclude(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		assert.ErrorIs(t, err, ErrInvalidSignatures)
+	})
+
+	t.Run("underfunded channel pays out in order", func(t *testing.T) {
+		tc := getTestChannel(t, 5, 3)
+		_, err := tc.channel.FundChannel(context.Background(), tc.participants[0], tc.keys[0])
+		require.NoError(t, err)
+		for _, key := range tc.keys {
+			_, err := tc.channel.ApproveChannelFunding(key)
+			require.NoError(t, err)
+		}
+		signatures := tc.trade(t, 0, true)
+
+		_, err = tc.channel.Conclude(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[0].Address))
+		assert.Zero(t, tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address).Sign())
+
+		status, err := tc.adjudicator.UnpackStatus(nil, tc.channel.ID())
+		assert.NoError(t, err)
+		assert.NotZero(t, status.Fingerprint.Sign())
+	})
+}
+
+func TestChallenge(t *testing.T) {
+	tc := getTestChannel(t, 5, 3)
+	tc.fund(t)
+	signatures := tc.trade(t, 2, false)
+	require.NoError(t, tc.challenge(t, signatures))
+
+	s := tc.channel.CurrentState()
+	status, err := tc.adjudicator.UnpackStatus(nil, tc.channel.ID())
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(int64(s.TurnNum)), status.TurnNumRecord)
+	assert.Equal(t, tc.adjudicator.Time()+s.ChallengeDuration.Uint64(), status.FinalizesAt.Uint64())
+
+	t.Run("challenge with the same turn number", func(t *testing.T) {
+		assert.ErrorIs(t, tc.challenge(t, signatures), ErrTurnNumRecordNotIncreased)
+	})
+
+	outcomeBytes, err := s.Outcome.Encode()
+	require.NoError(t, err)
+	stateHash, err := s.Hash()
+	require.NoError(t, err)
+
+	t.Run("funds are locked until challenge expires", func(t *testing.T) {
+		_, err := tc.adjudicator.TransferAllAssets(&bind.TransactOpts{}, tc.channel.ID(), outcomeBytes, stateHash)
+		assert.ErrorIs(t, err, ErrChannelNotFinalized)
+	})
+
+	tc.adjudicator.IncreaseTime(time.Duration(s.ChallengeDuration.Int64()) * time.Second)
+
+	t.Run("incorrect fingerprint", func(t *testing.T) {
+		_, err := tc.adjudicator.TransferAllAssets(&bind.TransactOpts{}, tc.channel.ID(), outcomeBytes, [32]byte{})
+		assert.ErrorIs(t, err, ErrIncorrectFingerprint)
+	})
+
+	_, err = tc.adjudicator.Transfer(&bind.TransactOpts{}, big.NewInt(0), tc.channel.ID(), outcomeBytes, stateHash, []*big.Int{big.NewInt(1)})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+
+	t.Run("stale outcome after transfer", func(t *testing.T) {
+		_, err := tc.adjudicator.TransferAllAssets(&bind.TransactOpts{}, tc.channel.ID(), outcomeBytes, stateHash)
+		assert.ErrorIs(t, err, ErrIncorrectFingerprint)
+	})
+
+	s.Outcome[0].Allocations[1].Amount = big.NewInt(0)
+	outcomeBytes, err = s.Outcome.Encode()
+	require.NoError(t, err)
+	_, err = tc.adjudicator.TransferAllAssets(&bind.TransactOpts{}, tc.channel.ID(), outcomeBytes, stateHash)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(3), tc.adjudicator.Payout(common.Address{}, tc.participants[0].Address))
+
+	holdings, err := tc.channel.CheckHoldings(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, holdings.Sign())
+}
+
+func TestValidTransition(t *testing.T) {
+	adjudicator := NewAdjudicator(chainID)
+	appDefinition := common.HexToAddress("0x01")
+	a := nitro.IForceMoveAppVariablePart{Outcome: []byte{1}, AppData: []byte{1}}
+	b := nitro.IForceMoveAppVariablePart{Outcome: []byte{2}, AppData: []byte{1}}
+
+	tests := []struct {
+		name      string
+		isFinalAB [2]bool
+		b         nitro.IForceMoveAppVariablePart
+		turnNumB  int64
+		err       error
+	}{
+		{"final state keeps outcome", [2]bool{false, true}, a, 3, nil},
+		{"final state changes outcome", [2]bool{false, true}, b, 3, ErrOutcomeChange},
+		{"final state is followed by non final", [2]bool{true, false}, a, 5, ErrIsFinalRetrograde},
+		{"setup state changes outcome", [2]bool{}, b, 2, ErrOutcomeChange},
+		{"unregistered app", [2]bool{}, b, 4, ErrUnknownApp},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			valid, err := adjudicator.ValidTransition(nil, big.NewInt(2), test.isFinalAB, [2]nitro.IForceMoveAppVariablePart{a, test.b}, big.NewInt(test.turnNumB), appDefinition)
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.err == nil, valid)
+		})
+	}
+
+	adjudicator.RegisterApp(appDefinition, TrivialApp)
+	valid, err := adjudicator.ValidTransition(nil, big.NewInt(2), [2]bool{}, [2]nitro.IForceMoveAppVariablePart{a, b}, big.NewInt(4), appDefinition)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
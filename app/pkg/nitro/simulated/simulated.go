@@ -0,0 +1,183 @@
+package simulated
+
+import (
+	"app/pkg/nitro"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrArtifactNotFound  = errors.New("simulated: contract artifact not found")
+	ErrTransactionFailed = errors.New("simulated: transaction failed")
+)
+
+const gasLimit = 30000000
+
+var (
+	// ChainID is the chain id of the simulated backend.
+	ChainID = big.NewInt(1337)
+	// Balance is the initial balance of every funded account, 1000 ETH.
+	Balance = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+)
+
+// artifacts stores ABI and bytecode of the contracts deployed by tests,
+// regenerate them with `npm run contracts:export-artifacts` in contracts.
+//
+//go:embed artifacts
+var artifacts embed.FS
+
+// Artifact stores ABI and bytecode of a compiled contract.
+type Artifact struct {
+	ABI      abi.ABI
+	Bytecode []byte
+}
+
+// Backend is a simulated Ethereum backend with deployed NitroAdjudicator.
+type Backend struct {
+	*backends.SimulatedBackend
+	Adjudicator        *nitro.NitroAdjudicator
+	AdjudicatorAddress common.Address
+	deployer           *bind.TransactOpts
+}
+
+// LoadArtifact returns embedded artifact of the compiled contract, e.g. HashLockedSwap.
+func LoadArtifact(name string) (*Artifact, error) {
+	data, err := artifacts.ReadFile("artifacts/" + name + ".abi")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrArtifactNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contractABI, err := abi.JSON(strings.NewReader(string(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	bytecode, err := artifacts.ReadFile("artifacts/" + name + ".bin")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Artifact{ABI: contractABI, Bytecode: common.FromHex(strings.TrimSpace(string(bytecode)))}, nil
+}
+
+// NewBackend returns a new simulated backend with funded accounts and deployed NitroAdjudicator.
+func NewBackend(accounts ...common.Address) (*Backend, error) {
+	artifact, err := LoadArtifact("NitroAdjudicator")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	deployer, err := bind.NewKeyedTransactorWithChainID(key, ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	alloc := core.GenesisAlloc{deployer.From: {Balance: Balance}}
+	for _, account := range accounts {
+		alloc[account] = core.GenesisAccount{Balance: Balance}
+	}
+
+	backend := &Backend{
+		SimulatedBackend: backends.NewSimulatedBackend(alloc, gasLimit),
+		deployer:         deployer,
+	}
+
+	address, _, err := backend.Deploy(artifact)
+	if err != nil {
+		backend.Close()
+		return nil, err
+	}
+
+	adjudicator, err := nitro.NewNitroAdjudicator(address, backend)
+	if err != nil {
+		backend.Close()
+		return nil, err
+	}
+	backend.Adjudicator = adjudicator
+	backend.AdjudicatorAddress = address
+
+	return backend, nil
+}
+
+// Client returns nitro client connected to the simulated backend.
+func (b *Backend) Client() nitro.Client {
+	return nitro.Client{Adjudicator: b.Adjudicator, ChainID: ChainID, Chain: b, Events: b.Adjudicator}
+}
+
+// Deploy deploys contract with supplied constructor params and returns its address and bound contract.
+func (b *Backend) Deploy(artifact *Artifact, params ...interface{}) (common.Address, *bind.BoundContract, error) {
+	address, tx, contract, err := bind.DeployContract(b.deployer, artifact.ABI, artifact.Bytecode, b, params...)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	if _, err := b.Mine(tx); err != nil {
+		return common.Address{}, nil, err
+	}
+
+	return address, contract, nil
+}
+
+// Mine commits pending transactions into a new block and returns receipt of the transaction.
+// An error is thrown if the transaction is reverted.
+func (b *Backend) Mine(tx *types.Transaction) (*types.Receipt, error) {
+	b.Commit()
+
+	receipt, err := b.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, fmt.Errorf("%w: %s", ErrTransactionFailed, tx.Hash())
+	}
+
+	return receipt, nil
+}
+
+// Balance returns balance of the account at the latest block.
+func (b *Backend) Balance(account common.Address) (*big.Int, error) {
+	return b.BalanceAt(context.Background(), account, nil)
+}
+
+// IncreaseTime moves time of the next block forward and commits it, e.g. to let challenge expire.
+func (b *Backend) IncreaseTime(d time.Duration) error {
+	if err := b.AdjustTime(d); err != nil {
+		return err
+	}
+	b.Commit()
+
+	return nil
+}
+
+// Key returns a new private key and address of an account to be funded by the backend.
+func Key() ([]byte, common.Address, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	return crypto.FromECDSA(key), crypto.PubkeyToAddress(key.PublicKey), nil
+}
@@ -0,0 +1,56 @@
+package nitro
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ChannelMode represents mode of the channel on-chain.
+type ChannelMode int
+
+const (
+	// Open channel has no ongoing challenge.
+	Open ChannelMode = iota
+	// Challenge channel finalizes when challenge duration passes unless challenge is cleared.
+	Challenge
+	// Finalized channel can't be updated anymore, its funds can be transferred.
+	Finalized
+)
+
+// String returns name of the channel mode.
+func (m ChannelMode) String() string {
+	switch m {
+	case Open:
+		return "Open"
+	case Challenge:
+		return "Challenge"
+	case Finalized:
+		return "Finalized"
+	default:
+		return "Unknown"
+	}
+}
+
+// Mode returns mode of the channel from finalization time and current block timestamp.
+// Zero finalizesAt means channel doesn't finalize.
+func Mode(finalizesAt, now uint64) ChannelMode {
+	switch {
+	case finalizesAt == 0:
+		return Open
+	case finalizesAt <= now:
+		return Finalized
+	default:
+		return Challenge
+	}
+}
+
+var bytes32Type, _ = abi.NewType("bytes32", "", nil)
+
+// Fingerprint returns the last 160 bits of state and outcome hashes as stored in channel status.
+func Fingerprint(stateHash, outcomeHash [32]byte) *big.Int {
+	encoded, _ := abi.Arguments{{Type: bytes32Type}, {Type: bytes32Type}}.Pack(stateHash, outcomeHash)
+
+	return new(big.Int).SetBytes(crypto.Keccak256(encoded)[12:])
+}
@@ -2,14 +2,18 @@ package protocol
 
 import (
 	"app/pkg/eth/gasprice"
+	"context"
 	"errors"
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	chl "github.com/statechannels/go-nitro/channel"
 	"github.com/statechannels/go-nitro/channel/state"
+	ntypes "github.com/statechannels/go-nitro/types"
 )
 
 var (
@@ -22,14 +26,39 @@ var (
 )
 
 // Channel represents information about current state, channel info.
+// Channel is safe for concurrent use, state proposals are not.
 type Channel struct {
 	initProposal *InitProposal
-	lastState    *state.State
-	c            chl.Channel
+
+	// mu guards the state of the channel below.
+	mu        sync.RWMutex
+	lastState *state.State
+	c         chl.Channel
+	// registered is outcome left on-chain after the latest confirmed payout.
+	registered *registeredOutcome
+	// pendingPayouts are outcomes left by submitted payout transactions keyed by transaction hash,
+	// outcome becomes registered once its transaction is confirmed.
+	pendingPayouts map[common.Hash]*registeredOutcome
+	// closed is set when transaction which pays out remaining funds of the channel is confirmed.
+	closed bool
+	// closingTx is the hash of submitted transaction which pays out remaining funds of the channel.
+	closingTx *common.Hash
+	// equivocations are conflicting states signed by participants, states on top of them aren't signed.
+	equivocations []Equivocation
+
+	// subsMu guards subscriptions, events are published while the channel lock is held.
+	subsMu        sync.Mutex
+	subscriptions map[*Subscription]struct{}
 }
 
 // InitChannel opens channel with participant who was requested opening a channel.
+// An error is thrown if the init proposal is invalid.
 func InitChannel(initProposal *InitProposal, participantIndex uint) (*Channel, error) {
+	err := initProposal.Validate()
+	if err != nil {
+		return &Channel{}, err
+	}
+
 	c, err := chl.New(*initProposal.State, participantIndex)
 	if err != nil {
 		return &Channel{}, err
@@ -45,6 +74,9 @@ func InitChannel(initProposal *InitProposal, participantIndex uint) (*Channel, e
 // ApproveChannelInit add participant's signature to the prefund state.
 // It returns signed state signature.
 func (channel *Channel) ApproveInitChannel(privateKey []byte) (state.Signature, error) {
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+
 	if channel.c.PreFundComplete() {
 		return state.Signature{}, ErrCompletedState
 	}
@@ -60,8 +92,12 @@ func (channel *Channel) ApproveInitChannel(privateKey []byte) (state.Signature,
 
 // FundChannel deposits funds to already opened state channel.
 // It returns on-chain transaction with detailed information.
-func (channel *Channel) FundChannel(p *Participant, privateKey []byte, opts ...gasprice.Station) (*types.Transaction, error) {
-	if !channel.c.PreFundComplete() {
+func (channel *Channel) FundChannel(ctx context.Context, p *Participant, privateKey []byte, opts ...gasprice.Station) (*types.Transaction, error) {
+	channel.mu.RLock()
+	preFundComplete := channel.c.PreFundComplete()
+	channel.mu.RUnlock()
+
+	if !preFundComplete {
 		return &types.Transaction{}, ErrIncompleteState
 	}
 
@@ -72,15 +108,15 @@ func (channel *Channel) FundChannel(p *Participant, privateKey []byte, opts ...g
 	// Construct TransactionOpts based on options
 	var transactOpts bind.TransactOpts
 	if len(opts) == 0 {
-		transactOpts = bind.TransactOpts{From: p.Address, Signer: signerFn, Value: p.LockedAmount}
+		transactOpts = bind.TransactOpts{From: p.Address, Signer: signerFn, Value: p.LockedAmount, Context: ctx}
 	} else {
 		transactOpts = bind.TransactOpts{
 			GasPrice: opts[0].GasPrice, GasLimit: opts[0].GasLimit,
-			From: p.Address, Signer: signerFn, Value: p.LockedAmount,
+			From: p.Address, Signer: signerFn, Value: p.LockedAmount, Context: ctx,
 		}
 	}
 
-	expectedHeld, err := channel.CheckHoldings()
+	expectedHeld, err := channel.CheckHoldings(ctx)
 	if err != nil {
 		return &types.Transaction{}, err
 	}
@@ -96,6 +132,9 @@ func (channel *Channel) FundChannel(p *Participant, privateKey []byte, opts ...g
 // ApproveChannelFunding signs postfund state after funding channel.
 // It returns signed state signature.
 func (channel *Channel) ApproveChannelFunding(privateKey []byte) (state.Signature, error) {
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+
 	if channel.c.PostFundComplete() {
 		return state.Signature{}, ErrCompletedState
 	}
@@ -115,13 +154,30 @@ func (channel *Channel) ApproveChannelFunding(privateKey []byte) (state.Signatur
 
 // ProposeState constructs new state with specified liability structure and signs proposed state
 // by participant who initiated proposal, returns this state proposal.
-// TODO
-// Now system could generate as many states as can
-// Need to block ability to generate new state without approving prev one
+// Expired pending liabilities are reverted in the proposed state.
+// Proposed state is a copy of the last state with the next turn number, it becomes the last state of the channel
+// once it is signed, so the channel is unchanged by proposals which are never signed.
+// If turn taking is enabled, only the mover of the new turn proposes the state.
 func (channel *Channel) ProposeState() (*StateProposal, error) {
-	lastStateNum := uint64(channel.lastState.TurnNum + 1)
-	channel.lastState.TurnNum = lastStateNum
-	stProposal, err := NewStateProposal(channel.lastState)
+	channel.mu.Lock()
+	turnNum := channel.lastState.TurnNum + 1
+	if err := channel.checkProposer(turnNum); err != nil {
+		channel.mu.Unlock()
+		return &StateProposal{}, err
+	}
+	proposed := cloneState(*channel.lastState)
+	proposed.TurnNum = turnNum
+	stProposal, err := NewStateProposalWithCodec(&proposed, channel.codec())
+	if err != nil {
+		channel.mu.Unlock()
+		return &StateProposal{}, err
+	}
+	channel.mu.Unlock()
+
+	stProposal.assets = channel.initProposal.Contract.Assets
+	stProposal.risk = channel.initProposal.Contract.Risk
+
+	_, err = stProposal.RevertExpiredLiabilities(time.Now().Unix())
 	if err != nil {
 		return &StateProposal{}, err
 	}
@@ -129,26 +185,62 @@ func (channel *Channel) ProposeState() (*StateProposal, error) {
 	return stProposal, nil
 }
 
+// codec returns app codec of the channel.
+func (channel *Channel) codec() AppCodec {
+	if channel.initProposal.Codec == nil {
+		return LiabilitiesCodec{Assets: channel.initProposal.Contract.Assets}
+	}
+
+	return channel.initProposal.Codec
+}
+
 // SignState adds a participant's signature to the proposed state and returns signed state signature.
-// An error is thrown if the signature is invalid.
-func (channel *Channel) SignState(stateProposal *StateProposal, privateKey []byte) (state.Signature, error) {
+// An error is thrown if the signature is invalid, proposed state is on top of equivocated turn or isn't a valid transition
+// from the latest supported state, context is used when transition is validated on-chain.
+// If turn taking is enabled, the mover of the turn signs the state first.
+func (channel *Channel) SignState(ctx context.Context, stateProposal *StateProposal, privateKey []byte) (state.Signature, error) {
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+
+	err := channel.checkEquivocatedTurn(stateProposal.state)
+	if err != nil {
+		return state.Signature{}, err
+	}
+
+	err = channel.validateTransition(ctx, stateProposal.state)
+	if err != nil {
+		return state.Signature{}, err
+	}
+
+	signer, err := addressOf(privateKey)
+	if err != nil {
+		return state.Signature{}, err
+	}
+
+	err = channel.checkMoverSigned(stateProposal.state, signer)
+	if err != nil {
+		return state.Signature{}, err
+	}
+
 	signature, err := channel.signState(stateProposal.state, privateKey)
 	if err != nil {
 		return state.Signature{}, err
 	}
 
 	// if participant agrees only on specific state, system need to update last state in agreement
-	if !channel.lastState.Equal(*stateProposal.state) {
-		channel.lastState = stateProposal.state
+	if stateProposal.state.TurnNum >= channel.lastState.TurnNum && !channel.lastState.Equal(*stateProposal.state) {
+		signed := cloneState(*stateProposal.state)
+		channel.lastState = &signed
 	}
 
 	return signature, nil
 }
 
 // Conclude transfer all participants funds to the destination addresses and close state channel.
-// It returns on-chain transaction with detailed information.
-func (channel *Channel) Conclude(p *Participant, privateKey []byte, participantSignatures map[common.Address]state.Signature, opts ...gasprice.Station) (*types.Transaction, error) {
-	lastState := channel.lastState
+// It returns on-chain transaction with detailed information, channel is closed once the transaction is confirmed
+// with ConfirmTransaction.
+func (channel *Channel) Conclude(ctx context.Context, p *Participant, privateKey []byte, participantSignatures map[common.Address]state.Signature, opts ...gasprice.Station) (*types.Transaction, error) {
+	lastState := channel.currentState()
 	if !lastState.IsFinal {
 		return &types.Transaction{}, ErrNotFinalState
 	}
@@ -159,13 +251,13 @@ func (channel *Channel) Conclude(p *Participant, privateKey []byte, participantS
 	// Construct TransactionOpts based on options
 	var transactOpts bind.TransactOpts
 	if len(opts) == 0 {
-		transactOpts = bind.TransactOpts{From: p.Address, Signer: signerFn}
+		transactOpts = bind.TransactOpts{From: p.Address, Signer: signerFn, Context: ctx}
 	} else {
-		transactOpts = bind.TransactOpts{GasPrice: opts[0].GasPrice, GasLimit: opts[0].GasLimit, From: p.Address, Signer: signerFn}
+		transactOpts = bind.TransactOpts{GasPrice: opts[0].GasPrice, GasLimit: opts[0].GasLimit, From: p.Address, Signer: signerFn, Context: ctx}
 	}
 
-	finalTurnNum := big.NewInt(int64(channel.lastState.TurnNum))
-	concludeParams, err := buildConcludeParams(lastState, participantSignatures)
+	finalTurnNum := big.NewInt(int64(lastState.TurnNum))
+	concludeParams, err := buildConcludeParams(&lastState, participantSignatures)
 	if err != nil {
 		return &types.Transaction{}, err
 	}
@@ -183,6 +275,7 @@ func (channel *Channel) Conclude(p *Participant, privateKey []byte, participantS
 	if err != nil {
 		return &types.Transaction{}, err
 	}
+	channel.closing(concludeTransaction)
 
 	return concludeTransaction, nil
 }
@@ -210,18 +303,93 @@ func (channel *Channel) CheckSignature(signature state.Signature, s *state.State
 	return true, nil
 }
 
+// AddSignature adds a signature made by another participant to the given state.
+// ProposalReceived is published for the first signature of a new state, StateSupported once the state is supported.
+// ErrEquivocation is returned if the participant has already signed a different state with the same turn number,
+// ErrMoverNotSigned if turn taking is enabled and the mover of the turn hasn't signed the state first.
+// An error is thrown if the signature is invalid or doesn't belong to the participant list, or if liabilities of the state
+// don't pass asset registry and risk policy of the contract.
+func (channel *Channel) AddSignature(s *state.State, signature state.Signature) error {
+	_, err := channel.CheckSignature(signature, s)
+	if err != nil {
+		return err
+	}
+
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+
+	if err := channel.detectEquivocation(s, signature); err != nil {
+		return err
+	}
+
+	signer, err := s.RecoverSigner(signature)
+	if err != nil {
+		return err
+	}
+
+	if err := channel.checkMoverSigned(s, signer); err != nil {
+		return err
+	}
+
+	if err := channel.validateLiabilities(*s); err != nil {
+		return err
+	}
+
+	turnNum, supported := channel.supportedTurnNum()
+	known, found := channel.c.SignedStateForTurnNum[s.TurnNum]
+	proposal := (!found || !known.State().Equal(*s)) && (!supported || s.TurnNum > turnNum)
+
+	ok := channel.c.AddStateWithSignature(*s, signature)
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	if proposal {
+		channel.publish(ProposalReceived{channelEvent: channelEvent{channel.c.Id}, State: cloneState(*s)})
+	}
+	channel.publishSupported(turnNum, supported)
+
+	if s.TurnNum >= channel.lastState.TurnNum && !channel.lastState.Equal(*s) {
+		signed := cloneState(*s)
+		channel.lastState = &signed
+	}
+
+	return nil
+}
+
+// ID returns state channel identifier.
+func (channel *Channel) ID() ntypes.Destination {
+	return channel.c.Id
+}
+
 // CurrentState returns information about current state.
 func (channel *Channel) CurrentState() state.State {
-	return *channel.lastState
+	return channel.currentState()
+}
+
+// PreFundState returns a copy of the prefund state.
+func (channel *Channel) PreFundState() state.State {
+	channel.mu.RLock()
+	defer channel.mu.RUnlock()
+
+	return cloneState(channel.c.PreFundState())
+}
+
+// PostFundState returns a copy of the postfund state.
+func (channel *Channel) PostFundState() state.State {
+	channel.mu.RLock()
+	defer channel.mu.RUnlock()
+
+	return cloneState(channel.c.PostFundState())
 }
 
 // CheckHoldings returns current holdings for already opened state channel per asset.
-func (channel *Channel) CheckHoldings() (*big.Int, error) {
+func (channel *Channel) CheckHoldings(ctx context.Context) (*big.Int, error) {
 	channelID := channel.c.Id
 	contract := channel.initProposal.Contract
 	adjudicator := contract.Client.Adjudicator
 
-	holdings, err := adjudicator.Holdings(&bind.CallOpts{}, channel.initProposal.Contract.AssetAddress, channelID)
+	holdings, err := adjudicator.Holdings(&bind.CallOpts{Context: ctx}, channel.initProposal.Contract.AssetAddress, channelID)
 	if err != nil {
 		return nil, err
 	}
@@ -231,10 +399,21 @@ func (channel *Channel) CheckHoldings() (*big.Int, error) {
 
 // StateIsFinal returns true if current state is final, false otherwise.
 func (channel *Channel) StateIsFinal() bool {
+	channel.mu.RLock()
+	defer channel.mu.RUnlock()
+
 	return channel.lastState.IsFinal
 }
 
-// signState adds a participant's signature to the newState.
+// currentState returns a copy of the last state.
+func (channel *Channel) currentState() state.State {
+	channel.mu.RLock()
+	defer channel.mu.RUnlock()
+
+	return cloneState(*channel.lastState)
+}
+
+// signState adds a participant's signature to the newState, StateSupported is published if the state gets supported.
 // An error is thrown if the signature is invalid.
 func (channel *Channel) signState(newState *state.State, privateKey []byte) (state.Signature, error) {
 	signature, err := newState.Sign(privateKey)
@@ -242,10 +421,23 @@ func (channel *Channel) signState(newState *state.State, privateKey []byte) (sta
 		return state.Signature{}, err
 	}
 
+	turnNum, supported := channel.supportedTurnNum()
 	ok := channel.c.AddStateWithSignature(*newState, signature)
 	if !ok {
 		return state.Signature{}, ErrInvalidSignature
 	}
+	channel.publishSupported(turnNum, supported)
 
 	return signature, nil
 }
+
+// cloneState returns a copy of the state with copied variable part, fixed part is shared.
+// state.Clone isn't used as it drops app data.
+func cloneState(s state.State) state.State {
+	clone := s
+	clone.AppData = make(ntypes.Bytes, len(s.AppData))
+	copy(clone.AppData, s.AppData)
+	clone.Outcome = s.Outcome.Clone()
+
+	return clone
+}
@@ -1,14 +1,19 @@
 package protocol
 
 import (
+	"app/internal/liability"
 	"app/pkg/nitro"
+	"context"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
 	"github.com/statechannels/go-nitro/crypto"
 	"github.com/statechannels/go-nitro/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -19,14 +24,16 @@ var (
 func getChannel() (*Channel, error) {
 	contract := NewContract(nitro.Client{ChainID: big.NewInt(2)}, common.HexToAddress("0x"))
 	proposal := NewInitProposal(participant1, contract)
-	proposal.AddParticipant(participant2)
+	if err := proposal.AddParticipant(participant2); err != nil {
+		return nil, err
+	}
 
 	ch, err := InitChannel(proposal, 0)
 	return ch, err
 }
 func TestInitChannel(t *testing.T) {
 	t.Run("successful channel initialization", func(t *testing.T) {
-		participant := NewParticipant(common.HexToAddress("0x01"), types.Destination(common.HexToHash("0x01")), uint(1), big.NewInt(2))
+		participant := NewParticipant(common.HexToAddress("0x01"), types.Destination(common.HexToHash("0x01")), uint(0), big.NewInt(2))
 		contract := NewContract(nitro.Client{ChainID: big.NewInt(2)}, common.HexToAddress("0x"))
 		proposal := NewInitProposal(participant, contract)
 
@@ -46,6 +53,15 @@ func TestInitChannel(t *testing.T) {
 		_, err := InitChannel(proposal, 0)
 		assert.Error(t, err)
 	})
+
+	t.Run("invalid proposal", func(t *testing.T) {
+		participant := NewParticipant(common.HexToAddress("0x01"), types.Destination(common.HexToHash("0x01")), uint(1), big.NewInt(2))
+		contract := NewContract(nitro.Client{ChainID: big.NewInt(2)}, common.HexToAddress("0x"))
+		proposal := NewInitProposal(participant, contract)
+
+		_, err := InitChannel(proposal, 0)
+		assert.ErrorIs(t, err, ErrParticipantIndex)
+	})
 }
 
 func TestApproveChannelInit(t *testing.T) {
@@ -116,7 +132,7 @@ func TestFundChannel(t *testing.T) {
 		privKeys[participant2] = common.Hex2Bytes("df57089febbacf7ba0bc227dafbffa9fc08a93fdc68e1e42411a14efcf23656e")
 
 		for p, key := range privKeys {
-			_, err := ch.FundChannel(p, key)
+			_, err := ch.FundChannel(context.Background(), p, key)
 			assert.Error(t, err, ErrIncompleteState)
 		}
 	})
@@ -222,13 +238,43 @@ func TestProposeState(t *testing.T) {
 	assert.Equal(t, uint64(1), ch.lastState.TurnNum)
 
 	t.Run("propose state", func(t *testing.T) {
-		_, err = ch.ProposeState()
+		sp, err := ch.ProposeState()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), sp.TurnNum())
+		assert.Equal(t, uint64(1), ch.lastState.TurnNum)
+
+		// proposal which isn't signed doesn't change the channel
+		exit := sp.Outcome()
+		exit[0].Allocations[0].Amount = big.NewInt(0)
+		sp.SetOutcome(exit)
+		assert.Equal(t, big.NewInt(2), ch.lastState.Outcome[0].Allocations[0].Amount)
+
+		sp, err = ch.ProposeState()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), sp.TurnNum())
+		assert.Equal(t, uint64(1), ch.lastState.TurnNum)
+	})
+
+	t.Run("expired liabilities are reverted", func(t *testing.T) {
+		sp, err := ch.ProposeState()
+		assert.NoError(t, err)
+
+		expiry := time.Now().Add(-time.Minute).Unix()
+		assert.NoError(t, sp.PendingLiabilityWithReference(0, 1, "ETH", decimal.NewFromFloat(1), "order-1", expiry))
+		assert.NoError(t, sp.PendingLiabilityWithReference(0, 1, "ETH", decimal.NewFromFloat(2), "order-2", 0))
+		assert.NoError(t, sp.ApproveLiabilities())
+		_, err = ch.SignState(context.Background(), sp, privKeys[participant1])
 		assert.NoError(t, err)
 		assert.Equal(t, uint64(2), ch.lastState.TurnNum)
 
-		_, err = ch.ProposeState()
+		sp, err = ch.ProposeState()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), sp.TurnNum())
+		assert.Equal(t, map[liability.Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, sp.LiabilityState()[0][1].PendingAmounts())
+
+		ls, err := liability.DecodeFromBytes(sp.AppData())
 		assert.NoError(t, err)
-		assert.Equal(t, uint64(3), ch.lastState.TurnNum)
+		assert.Equal(t, "order-2", ls[0][1].Pending["ETH"][0].Reference)
 	})
 }
 
@@ -256,14 +302,15 @@ func TestSignState(t *testing.T) {
 
 	stateProposal, err := ch.ProposeState()
 	assert.NoError(t, err)
-	assert.Equal(t, uint64(2), ch.lastState.TurnNum)
+	assert.Equal(t, uint64(2), stateProposal.TurnNum())
+	assert.Equal(t, uint64(1), ch.lastState.TurnNum)
 
 	t.Run("invalid keys", func(t *testing.T) {
 		privKeys[participant1] = []byte{}
 		privKeys[participant2] = []byte{}
 
 		for _, key := range privKeys {
-			_, err := ch.SignState(stateProposal, key)
+			_, err := ch.SignState(context.Background(), stateProposal, key)
 			assert.Error(t, err)
 		}
 	})
@@ -273,7 +320,7 @@ func TestSignState(t *testing.T) {
 		privKeys[participant2] = common.Hex2Bytes("df57089febbacf7ba0bc227dafbffa9fc08a93fdc68e1e42411a14efcf23656e")
 
 		for _, key := range privKeys {
-			_, err := ch.SignState(stateProposal, key)
+			_, err := ch.SignState(context.Background(), stateProposal, key)
 			assert.NoError(t, err)
 		}
 		assert.Equal(t, uint64(2), ch.lastState.TurnNum)
@@ -304,18 +351,82 @@ func TestConcludeChannel(t *testing.T) {
 
 	stateProposal, err := ch.ProposeState()
 	assert.NoError(t, err)
-	assert.Equal(t, uint64(2), ch.lastState.TurnNum)
+	assert.Equal(t, uint64(2), stateProposal.TurnNum())
+	assert.Equal(t, uint64(1), ch.lastState.TurnNum)
 
 	participantSignatures := make(map[common.Address]crypto.Signature)
 	for p, key := range privKeys {
-		signature, err := ch.SignState(stateProposal, key)
+		signature, err := ch.SignState(context.Background(), stateProposal, key)
 		assert.NoError(t, err)
 		participantSignatures[p.Address] = signature
 	}
 	assert.Equal(t, uint64(2), ch.lastState.TurnNum)
 
 	t.Run("not final state", func(t *testing.T) {
-		_, err = ch.Conclude(participant1, privKeys[participant1], participantSignatures)
+		_, err = ch.Conclude(context.Background(), participant1, privKeys[participant1], participantSignatures)
 		assert.Error(t, err, ErrNotFinalState)
 	})
 }
+
+func TestAddSignature(t *testing.T) {
+	ch, err := getChannel()
+	assert.NoError(t, err)
+
+	preFundState := ch.CurrentState()
+	signature, err := preFundState.Sign(common.Hex2Bytes("df57089febbacf7ba0bc227dafbffa9fc08a93fdc68e1e42411a14efcf23656e"))
+	assert.NoError(t, err)
+
+	t.Run("signature of non participant", func(t *testing.T) {
+		foreignSignature, err := preFundState.Sign(common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000001"))
+		assert.NoError(t, err)
+
+		err = ch.AddSignature(&preFundState, foreignSignature)
+		assert.ErrorIs(t, err, ErrSignatureIsNotInList)
+	})
+
+	t.Run("successful signature adding", func(t *testing.T) {
+		err := ch.AddSignature(&preFundState, signature)
+		assert.NoError(t, err)
+
+		err = ch.AddSignature(&preFundState, signature)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+}
+
+func TestCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tc := getMockChannel(t, 5, 3)
+	tc.channel.initProposal.Contract.ValidateOnChain = true
+
+	_, err := tc.channel.CheckHoldings(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = tc.channel.FundChannel(ctx, tc.participants[0], tc.keys[0])
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = tc.channel.OnChainStatus(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	sp, err := tc.channel.ProposeState()
+	require.NoError(t, err)
+	require.NoError(t, sp.SetFinal())
+	_, err = tc.channel.SignState(ctx, sp, tc.keys[0])
+	assert.ErrorIs(t, err, context.Canceled)
+
+	signatures := make(map[common.Address]crypto.Signature)
+	for i, key := range tc.keys {
+		signature, err := tc.channel.SignState(context.Background(), sp, key)
+		require.NoError(t, err)
+		signatures[tc.participants[i].Address] = signature
+	}
+	_, err = tc.channel.Conclude(ctx, tc.participants[0], tc.keys[0], signatures)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = tc.channel.Finalize(ctx, tc.participants[0], tc.keys[0], signatures)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	holdings, err := tc.channel.CheckHoldings(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(8), holdings)
+}
@@ -0,0 +1,112 @@
+package protocol
+
+import (
+	"app/internal/asset"
+	"app/internal/liability"
+	"errors"
+
+	"github.com/statechannels/go-nitro/channel/state"
+)
+
+var (
+	ErrInvalidAppData          = errors.New("channel: app data doesn't match app codec")
+	ErrLiabilitiesNotSupported = errors.New("channel: channel app doesn't support liabilities")
+	ErrOutcomeChanged          = errors.New("channel: outcome of non-final state is changed")
+	ErrUnsettledOutcome        = errors.New("channel: final outcome doesn't match settlement of executed liabilities")
+)
+
+// AppCodec decodes, validates and encodes app data of the channel application.
+type AppCodec interface {
+	// Decode returns app decoded from state app data, empty app data is decoded to initial app.
+	Decode(appData []byte) (interface{}, error)
+	// Encode returns state app data of the app.
+	Encode(app interface{}) ([]byte, error)
+	// ValidTransition returns an error if application doesn't allow transition between apps.
+	// It is checked by Channel.SignState between apps of the latest supported state and the proposed state.
+	ValidTransition(from, to interface{}) error
+}
+
+// StateValidator is implemented by app codecs of applications which rules apply to the whole state,
+// e.g. to the outcome. It is checked in addition to ValidTransition of the codec.
+type StateValidator interface {
+	// ValidStateTransition returns an error if application doesn't allow transition between states.
+	ValidStateTransition(from, to state.State) error
+}
+
+// LiabilitiesCodec is the AppCodec of liabilities application, which app is liability.LiabilitiesState.
+// It is used by channels without codec with the asset registry of the channel contract.
+type LiabilitiesCodec struct {
+	// Assets converts executed liabilities to outcome units when final outcome is checked.
+	Assets *asset.Registry
+}
+
+// Decode returns liabilities state decoded from app data.
+func (LiabilitiesCodec) Decode(appData []byte) (interface{}, error) {
+	liabilitiesState, err := liability.DecodeFromBytes(appData)
+	if errors.Is(err, liability.ErrEmptyByteArray) {
+		return make(liability.LiabilitiesState), nil
+	}
+
+	return liabilitiesState, err
+}
+
+// Encode returns app data of liabilities state.
+func (LiabilitiesCodec) Encode(app interface{}) ([]byte, error) {
+	liabilitiesState, ok := app.(liability.LiabilitiesState)
+	if !ok {
+		return nil, ErrInvalidAppData
+	}
+
+	return liabilitiesState.EncodeToBytes()
+}
+
+// ValidTransition checks both apps are liabilities states. Liabilities themselves are agreed
+// by signatures of all participants, so any transition between them is allowed.
+func (LiabilitiesCodec) ValidTransition(from, to interface{}) error {
+	if _, ok := from.(liability.LiabilitiesState); !ok {
+		return ErrInvalidAppData
+	}
+	if _, ok := to.(liability.LiabilitiesState); !ok {
+		return ErrInvalidAppData
+	}
+
+	return nil
+}
+
+// ValidStateTransition checks that liabilities of the state refer to channel participants only
+// and funds are moved only by settlement: outcome of non-final state is unchanged, outcome of final state
+// is the previous outcome with executed liabilities settled.
+func (c LiabilitiesCodec) ValidStateTransition(from, to state.State) error {
+	app, err := c.Decode(to.AppData)
+	if err != nil {
+		return err
+	}
+
+	liabilitiesState, ok := app.(liability.LiabilitiesState)
+	if !ok {
+		return ErrInvalidAppData
+	}
+
+	err = liabilitiesState.ValidateParticipants(uint(len(to.Participants)))
+	if err != nil {
+		return err
+	}
+
+	if !to.IsFinal {
+		if !to.Outcome.Equal(from.Outcome) {
+			return ErrOutcomeChanged
+		}
+
+		return nil
+	}
+
+	settled, err := settle(from.Outcome, liabilitiesState, c.Assets)
+	if err != nil {
+		return err
+	}
+	if !to.Outcome.Equal(settled) {
+		return ErrUnsettledOutcome
+	}
+
+	return nil
+}
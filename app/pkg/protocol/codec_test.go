@@ -0,0 +1,142 @@
+package protocol
+
+import (
+	"app/internal/liability"
+	"app/pkg/nitro"
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errCounterDecreased = errors.New("counter: counter decreased")
+
+// counterCodec is the AppCodec of application which app is an increasing counter.
+type counterCodec struct{}
+
+func (counterCodec) Decode(appData []byte) (interface{}, error) {
+	if len(appData) == 0 {
+		return uint64(0), nil
+	}
+	if len(appData) != 8 {
+		return nil, ErrInvalidAppData
+	}
+
+	return binary.BigEndian.Uint64(appData), nil
+}
+
+func (counterCodec) Encode(app interface{}) ([]byte, error) {
+	counter, ok := app.(uint64)
+	if !ok {
+		return nil, ErrInvalidAppData
+	}
+
+	appData := make([]byte, 8)
+	binary.BigEndian.PutUint64(appData, counter)
+
+	return appData, nil
+}
+
+func (counterCodec) ValidTransition(from, to interface{}) error {
+	if to.(uint64) < from.(uint64) {
+		return errCounterDecreased
+	}
+
+	return nil
+}
+
+func TestLiabilitiesCodec(t *testing.T) {
+	codec := LiabilitiesCodec{}
+
+	app, err := codec.Decode([]byte{})
+	assert.NoError(t, err)
+	assert.Equal(t, make(liability.LiabilitiesState), app)
+
+	ls := make(liability.LiabilitiesState)
+	ls.AddPendingLiability(0, 1, "ETH", decimal.NewFromFloat(1))
+	appData, err := codec.Encode(ls)
+	assert.NoError(t, err)
+
+	app, err = codec.Decode(appData)
+	assert.NoError(t, err)
+	assert.Equal(t, ls, app)
+	assert.NoError(t, codec.ValidTransition(ls, app))
+
+	_, err = codec.Encode(uint64(1))
+	assert.ErrorIs(t, err, ErrInvalidAppData)
+	assert.ErrorIs(t, codec.ValidTransition(ls, uint64(1)), ErrInvalidAppData)
+}
+
+func TestChannelCodec(t *testing.T) {
+	contract := NewContract(nitro.Client{ChainID: big.NewInt(2)}, common.HexToAddress("0x"))
+	proposal := NewInitProposal(participant1, contract)
+	require.NoError(t, proposal.AddParticipant(participant2))
+	proposal.Codec = counterCodec{}
+
+	ch, err := InitChannel(proposal, 0)
+	assert.NoError(t, err)
+
+	key := common.Hex2Bytes("de9be858da4a475276426320d5e9262ecfc3ba460bfac56360bfa6c4c28b4ee0")
+	counterpartyKey := common.Hex2Bytes("df57089febbacf7ba0bc227dafbffa9fc08a93fdc68e1e42411a14efcf23656e")
+	for _, k := range [][]byte{key, counterpartyKey} {
+		_, err := ch.ApproveInitChannel(k)
+		assert.NoError(t, err)
+		_, err = ch.ApproveChannelFunding(k)
+		assert.NoError(t, err)
+	}
+
+	sp, err := ch.ProposeState()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), sp.App())
+
+	t.Run("set app", func(t *testing.T) {
+		err := sp.SetApp(uint64(5))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(5), sp.App())
+		assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 5}, []byte(sp.AppData()))
+
+		err = sp.SetApp("5")
+		assert.ErrorIs(t, err, ErrInvalidAppData)
+	})
+
+	t.Run("liabilities are not supported", func(t *testing.T) {
+		require.NoError(t, sp.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(1)))
+		err := sp.ApproveLiabilities()
+		assert.ErrorIs(t, err, ErrLiabilitiesNotSupported)
+		assert.Equal(t, uint64(5), sp.App())
+	})
+
+	t.Run("next proposal decodes app", func(t *testing.T) {
+		_, err := ch.SignState(context.Background(), sp, key)
+		assert.NoError(t, err)
+
+		next, err := ch.ProposeState()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(5), next.App())
+	})
+
+	t.Run("codec transition is checked before signing", func(t *testing.T) {
+		_, err := ch.SignState(context.Background(), sp, counterpartyKey)
+		assert.NoError(t, err)
+
+		next, err := ch.ProposeState()
+		assert.NoError(t, err)
+		assert.NoError(t, next.SetApp(uint64(4)))
+
+		_, err = ch.SignState(context.Background(), next, key)
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+		assert.Contains(t, err.Error(), errCounterDecreased.Error())
+		assert.Equal(t, uint64(2), ch.CurrentState().TurnNum)
+
+		assert.NoError(t, next.SetApp(uint64(6)))
+		_, err = ch.SignState(context.Background(), next, key)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), ch.CurrentState().TurnNum)
+	})
+}
@@ -0,0 +1,173 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests are meant to be run with the race detector: go test -race ./pkg/protocol
+
+func TestConcurrentSignatures(t *testing.T) {
+	tc := getMockChannel(t, 10, 10, 10, 10)
+	sp, err := tc.channel.ProposeState()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	signatures := make([]state.Signature, len(tc.keys))
+	errs := make([]error, len(tc.keys))
+	for i := range tc.keys {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			signatures[i], errs[i] = tc.channel.SignState(context.Background(), sp, tc.keys[i])
+		}(i)
+		go func() {
+			defer wg.Done()
+			tc.channel.CurrentState()
+			tc.channel.Lifecycle()
+			tc.channel.StateIsFinal()
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		assert.NoError(t, err)
+		valid, err := tc.channel.CheckSignature(signatures[i], sp.state)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	}
+
+	supported, err := tc.channel.c.LatestSupportedState()
+	require.NoError(t, err)
+	assert.Equal(t, sp.TurnNum(), supported.TurnNum)
+}
+
+func TestConcurrentProposals(t *testing.T) {
+	tc := getMockChannel(t, 100, 100)
+	postFundTurnNum := tc.channel.c.PostFundState().TurnNum
+
+	const proposals = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, proposals)
+	for i := 0; i < proposals; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs <- transfer(tc.channel, tc.keys, 1)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := tc.channel.OnChainStatus(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	supportedProposals := 0
+	for err := range errs {
+		if err == nil {
+			supportedProposals++
+			continue
+		}
+
+		// concurrent proposals of the same turn conflict, only one of them gets supported
+		if !errors.Is(err, ErrInvalidTransition) && !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	supported, err := tc.channel.c.LatestSupportedState()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, supportedProposals, 1)
+	assert.Equal(t, postFundTurnNum+uint64(supportedProposals), supported.TurnNum)
+	assert.Equal(t, big.NewInt(int64(100+supportedProposals)), supported.Outcome[0].Allocations[1].Amount)
+	assert.Equal(t, big.NewInt(200), supported.Outcome.TotalAllocated()[common.Address{}])
+}
+
+func TestFailedProposal(t *testing.T) {
+	tc := getMockChannel(t, 100, 100)
+	turnNum := tc.channel.CurrentState().TurnNum
+
+	sp, err := tc.channel.ProposeState()
+	require.NoError(t, err)
+	exit := sp.Outcome()
+	exit[0].Allocations[0].Amount = big.NewInt(1000)
+	sp.SetOutcome(exit)
+
+	_, err = tc.channel.SignState(context.Background(), sp, tc.keys[0])
+	require.ErrorIs(t, err, ErrInvalidTransition)
+	assert.Equal(t, turnNum, tc.channel.CurrentState().TurnNum)
+	assert.Equal(t, big.NewInt(100), tc.channel.CurrentState().Outcome[0].Allocations[0].Amount)
+
+	require.NoError(t, transfer(tc.channel, tc.keys, 1))
+	supported, err := tc.channel.c.LatestSupportedState()
+	require.NoError(t, err)
+	assert.Equal(t, turnNum+1, supported.TurnNum)
+}
+
+func TestConcurrentAddSignature(t *testing.T) {
+	tc := getMockChannel(t, 10, 10)
+
+	// counterparty keeps its own copy of the channel and sends signatures of its states
+	counterparty, err := InitChannel(tc.channel.initProposal, 1)
+	require.NoError(t, err)
+	for _, s := range []state.State{tc.channel.c.PreFundState(), tc.channel.c.PostFundState()} {
+		for _, key := range tc.keys {
+			require.NoError(t, counterparty.AddSignature(&s, signatureOf(t, s, key)))
+		}
+	}
+
+	var states []state.State
+	for i := 0; i < 10; i++ {
+		require.NoError(t, transfer(counterparty, tc.keys, 1))
+		states = append(states, counterparty.CurrentState())
+	}
+
+	// signatures of every participant arrive in order, participants are not synchronized
+	var wg sync.WaitGroup
+	for _, key := range tc.keys {
+		var signatures []state.Signature
+		for _, s := range states {
+			signatures = append(signatures, signatureOf(t, s, key))
+		}
+
+		wg.Add(2)
+		go func(signatures []state.Signature) {
+			defer wg.Done()
+			for i := range states {
+				assert.NoError(t, tc.channel.AddSignature(&states[i], signatures[i]))
+			}
+		}(signatures)
+		go func() {
+			defer wg.Done()
+			for range states {
+				tc.channel.CurrentState()
+				tc.channel.Lifecycle()
+			}
+		}()
+	}
+	wg.Wait()
+
+	current := tc.channel.CurrentState()
+	assert.True(t, counterparty.CurrentState().Equal(current))
+	supported, err := tc.channel.c.LatestSupportedState()
+	require.NoError(t, err)
+	assert.Equal(t, states[len(states)-1].TurnNum, supported.TurnNum)
+}
+
+// signatureOf returns signature of the state made with private key.
+func signatureOf(t *testing.T, s state.State, privateKey []byte) state.Signature {
+	signature, err := s.Sign(privateKey)
+	require.NoError(t, err)
+
+	return signature
+}
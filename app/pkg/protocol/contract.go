@@ -1,15 +1,23 @@
 package protocol
 
 import (
+	"app/internal/asset"
+	"app/internal/risk"
 	"app/pkg/nitro"
 
 	"github.com/ethereum/go-ethereum/common"
 )
 
 // Contract stores information about SC client and asset.
+// Assets is optional, when set liabilities are validated against assets in the channel.
+// Risk is optional, when set liabilities exceeding collateral ratio are rejected, it requires Assets.
+// ValidateOnChain enables validTransition call of the app before signing a state.
 type Contract struct {
-	Client       nitro.Client
-	AssetAddress common.Address
+	Client          nitro.Client
+	AssetAddress    common.Address
+	Assets          *asset.Registry
+	Risk            *risk.Policy
+	ValidateOnChain bool
 }
 
 // NewContract returns a new Contract from supplied params.
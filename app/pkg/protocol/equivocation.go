@@ -0,0 +1,140 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+)
+
+var (
+	ErrEquivocation        = errors.New("channel: participant signed conflicting states with the same turn number")
+	ErrEquivocatedTurn     = errors.New("channel: state is on top of equivocated turn")
+	ErrInvalidEquivocation = errors.New("channel: states don't prove equivocation")
+)
+
+// SignedState is a state with signature of a single participant.
+type SignedState struct {
+	State     state.State     `json:"state"`
+	Signature state.Signature `json:"signature"`
+}
+
+// Equivocation is evidence of participant signing two different states with the same turn number.
+type Equivocation struct {
+	ChannelID common.Hash    `json:"channelId"`
+	TurnNum   uint64         `json:"turnNum"`
+	Signer    common.Address `json:"signer"`
+	States    [2]SignedState `json:"states"`
+}
+
+// Verify returns an error if states of the equivocation are not different states of the channel
+// with the same turn number signed by the signer.
+func (e Equivocation) Verify() error {
+	if e.States[0].State.Equal(e.States[1].State) {
+		return ErrInvalidEquivocation
+	}
+
+	for _, signed := range e.States {
+		id, err := signed.State.ChannelId()
+		if err != nil {
+			return err
+		}
+
+		if common.Hash(id) != e.ChannelID || signed.State.TurnNum != e.TurnNum {
+			return ErrInvalidEquivocation
+		}
+
+		signer, err := signed.State.RecoverSigner(signed.Signature)
+		if err != nil {
+			return err
+		}
+
+		if signer != e.Signer {
+			return ErrInvalidEquivocation
+		}
+	}
+
+	return nil
+}
+
+// Equivocations returns detected equivocations of the channel participants in order of detection.
+func (channel *Channel) Equivocations() []Equivocation {
+	channel.mu.RLock()
+	defer channel.mu.RUnlock()
+
+	return append([]Equivocation(nil), channel.equivocations...)
+}
+
+// ExportEquivocations returns JSON encoded evidence of detected equivocations.
+func (channel *Channel) ExportEquivocations() ([]byte, error) {
+	return json.Marshal(channel.Equivocations())
+}
+
+// detectEquivocation returns ErrEquivocation if the signer of signature has already signed a different state
+// with the same turn number, the equivocation is recorded and published.
+// It must be called with the channel lock held.
+func (channel *Channel) detectEquivocation(s *state.State, signature state.Signature) error {
+	known, found := channel.c.SignedStateForTurnNum[s.TurnNum]
+	if !found || known.State().Equal(*s) {
+		return nil
+	}
+
+	signer, err := s.RecoverSigner(signature)
+	if err != nil {
+		return err
+	}
+
+	for i, address := range channel.c.Participants {
+		if address != signer || !known.HasSignatureForParticipant(uint(i)) {
+			continue
+		}
+
+		prior, err := known.GetParticipantSignature(uint(i))
+		if err != nil {
+			return err
+		}
+
+		if !channel.equivocated(s.TurnNum, signer) {
+			equivocation := Equivocation{
+				ChannelID: common.Hash(channel.c.Id),
+				TurnNum:   s.TurnNum,
+				Signer:    signer,
+				States: [2]SignedState{
+					{State: cloneState(known.State()), Signature: prior},
+					{State: cloneState(*s), Signature: signature},
+				},
+			}
+			channel.equivocations = append(channel.equivocations, equivocation)
+			channel.publish(EquivocationDetected{channelEvent: channelEvent{channel.c.Id}, Equivocation: equivocation})
+		}
+
+		return ErrEquivocation
+	}
+
+	return nil
+}
+
+// equivocated returns true if equivocation of the signer at the turn is already recorded.
+// It must be called with the channel lock held.
+func (channel *Channel) equivocated(turnNum uint64, signer common.Address) bool {
+	for _, e := range channel.equivocations {
+		if e.TurnNum == turnNum && e.Signer == signer {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkEquivocatedTurn returns ErrEquivocatedTurn if an equivocation is recorded at the turn of the state or before it.
+// It must be called with the channel lock held.
+func (channel *Channel) checkEquivocatedTurn(s *state.State) error {
+	for _, e := range channel.equivocations {
+		if e.TurnNum <= s.TurnNum {
+			return ErrEquivocatedTurn
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextTurnState returns state on top of the current state of the channel where amount is moved
+// from the first to the second participant.
+func nextTurnState(ch *Channel, amount int64) state.State {
+	s := ch.CurrentState()
+	s.TurnNum++
+	allocations := s.Outcome[0].Allocations
+	allocations[0].Amount = new(big.Int).Sub(allocations[0].Amount, big.NewInt(amount))
+	allocations[1].Amount = new(big.Int).Add(allocations[1].Amount, big.NewInt(amount))
+
+	return s
+}
+
+func TestEquivocation(t *testing.T) {
+	t.Run("conflicting states of the same signer", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		sub := tc.channel.Subscribe()
+		defer sub.Unsubscribe()
+
+		first, second := nextTurnState(tc.channel, 1), nextTurnState(tc.channel, 2)
+		require.NoError(t, tc.channel.AddSignature(&first, signatureOf(t, first, tc.keys[1])))
+		require.IsType(t, ProposalReceived{}, nextEvent(t, sub))
+
+		err := tc.channel.AddSignature(&second, signatureOf(t, second, tc.keys[1]))
+		assert.ErrorIs(t, err, ErrEquivocation)
+		assert.True(t, first.Equal(tc.channel.CurrentState()))
+
+		equivocations := tc.channel.Equivocations()
+		require.Len(t, equivocations, 1)
+		equivocation := equivocations[0]
+		assert.Equal(t, tc.participants[1].Address, equivocation.Signer)
+		assert.Equal(t, first.TurnNum, equivocation.TurnNum)
+		assert.True(t, first.Equal(equivocation.States[0].State))
+		assert.True(t, second.Equal(equivocation.States[1].State))
+		assert.NoError(t, equivocation.Verify())
+
+		e := nextEvent(t, sub)
+		require.IsType(t, EquivocationDetected{}, e)
+		assert.Equal(t, equivocation, e.(EquivocationDetected).Equivocation)
+
+		// equivocation is recorded once
+		err = tc.channel.AddSignature(&second, signatureOf(t, second, tc.keys[1]))
+		assert.ErrorIs(t, err, ErrEquivocation)
+		assert.Len(t, tc.channel.Equivocations(), 1)
+	})
+
+	t.Run("states on top of equivocated turn are not signed", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		first, second := nextTurnState(tc.channel, 1), nextTurnState(tc.channel, 2)
+		require.NoError(t, tc.channel.AddSignature(&first, signatureOf(t, first, tc.keys[1])))
+		require.ErrorIs(t, tc.channel.AddSignature(&second, signatureOf(t, second, tc.keys[1])), ErrEquivocation)
+
+		sp, err := NewStateProposal(&first)
+		require.NoError(t, err)
+		_, err = tc.channel.SignState(context.Background(), sp, tc.keys[0])
+		assert.ErrorIs(t, err, ErrEquivocatedTurn)
+
+		sp, err = tc.channel.ProposeState()
+		require.NoError(t, err)
+		_, err = tc.channel.SignState(context.Background(), sp, tc.keys[0])
+		assert.ErrorIs(t, err, ErrEquivocatedTurn)
+	})
+
+	t.Run("conflicting states of different signers", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		first, second := nextTurnState(tc.channel, 1), nextTurnState(tc.channel, 2)
+		require.NoError(t, tc.channel.AddSignature(&first, signatureOf(t, first, tc.keys[1])))
+
+		err := tc.channel.AddSignature(&second, signatureOf(t, second, tc.keys[0]))
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+		assert.Empty(t, tc.channel.Equivocations())
+	})
+}
+
+func TestExportEquivocations(t *testing.T) {
+	tc := getMockChannel(t, 5, 3)
+	first, second := nextTurnState(tc.channel, 1), nextTurnState(tc.channel, 2)
+	require.NoError(t, tc.channel.AddSignature(&first, signatureOf(t, first, tc.keys[1])))
+	require.ErrorIs(t, tc.channel.AddSignature(&second, signatureOf(t, second, tc.keys[1])), ErrEquivocation)
+
+	data, err := tc.channel.ExportEquivocations()
+	require.NoError(t, err)
+
+	var evidence []Equivocation
+	require.NoError(t, json.Unmarshal(data, &evidence))
+	require.Len(t, evidence, 1)
+	assert.Equal(t, common.Hash(tc.channel.ID()), evidence[0].ChannelID)
+	assert.NoError(t, evidence[0].Verify())
+
+	t.Run("tampered evidence", func(t *testing.T) {
+		forged := evidence[0]
+		forged.Signer = tc.participants[0].Address
+		assert.ErrorIs(t, forged.Verify(), ErrInvalidEquivocation)
+
+		forged = evidence[0]
+		forged.States[1] = forged.States[0]
+		assert.ErrorIs(t, forged.Verify(), ErrInvalidEquivocation)
+	})
+}
@@ -0,0 +1,276 @@
+package protocol
+
+import (
+	"app/pkg/nitro"
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/statechannels/go-nitro/channel/state"
+	ntypes "github.com/statechannels/go-nitro/types"
+)
+
+var (
+	ErrNoEventWatcher = errors.New("channel: contract client has no event watcher")
+)
+
+// Event is a lifecycle event of the channel delivered to subscribers.
+type Event interface {
+	ChannelID() ntypes.Destination
+}
+
+// channelEvent identifies the channel of the event.
+type channelEvent struct {
+	id ntypes.Destination
+}
+
+// ChannelID returns identifier of the channel the event belongs to.
+func (e channelEvent) ChannelID() ntypes.Destination {
+	return e.id
+}
+
+// ProposalReceived is emitted when another participant signs a state which isn't supported yet.
+type ProposalReceived struct {
+	channelEvent
+	State state.State
+}
+
+// StateSupported is emitted when a state with greater turn number gets signatures of all participants.
+type StateSupported struct {
+	channelEvent
+	State state.State
+}
+
+// FundingComplete is emitted when on-chain holdings of the channel reach the total of initial outcome.
+type FundingComplete struct {
+	channelEvent
+	Holdings *big.Int
+}
+
+// ChallengeRegistered is emitted when a challenge of the channel is registered on-chain.
+type ChallengeRegistered struct {
+	channelEvent
+	TurnNumRecord uint64
+	FinalizesAt   uint64
+}
+
+// Concluded is emitted when the channel is concluded on-chain.
+type Concluded struct {
+	channelEvent
+	FinalizesAt uint64
+}
+
+// EquivocationDetected is emitted when a participant signs conflicting states with the same turn number.
+type EquivocationDetected struct {
+	channelEvent
+	Equivocation Equivocation
+}
+
+// Subscription delivers events of the channel in order of occurrence.
+// Events are queued, so channel operations never wait for subscribers.
+type Subscription struct {
+	events chan Event
+	notify chan struct{}
+	quit   chan struct{}
+	once   sync.Once
+	remove func()
+
+	mu    sync.Mutex
+	queue []Event
+}
+
+// Events returns channel of events, it is closed after unsubscribing.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Unsubscribe stops delivery of events, queued events are dropped.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.remove()
+		close(s.quit)
+	})
+}
+
+// push queues the event for delivery.
+func (s *Subscription) push(e Event) {
+	s.mu.Lock()
+	s.queue = append(s.queue, e)
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// loop delivers queued events until unsubscribed.
+func (s *Subscription) loop() {
+	defer close(s.events)
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.notify:
+				continue
+			case <-s.quit:
+				return
+			}
+		}
+		e := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.events <- e:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// Subscribe returns subscription to events of the channel, it must be unsubscribed when no longer needed.
+func (channel *Channel) Subscribe() *Subscription {
+	sub := &Subscription{
+		events: make(chan Event),
+		notify: make(chan struct{}, 1),
+		quit:   make(chan struct{}),
+	}
+	sub.remove = func() {
+		channel.subsMu.Lock()
+		defer channel.subsMu.Unlock()
+
+		delete(channel.subscriptions, sub)
+	}
+
+	channel.subsMu.Lock()
+	if channel.subscriptions == nil {
+		channel.subscriptions = make(map[*Subscription]struct{})
+	}
+	channel.subscriptions[sub] = struct{}{}
+	channel.subsMu.Unlock()
+
+	go sub.loop()
+
+	return sub
+}
+
+// Watch subscribes to on-chain events of the channel and delivers them to subscribers of the channel.
+// Events are watched until the returned subscription is unsubscribed or the context is done,
+// the subscription error is context error or error of the event watcher.
+func (channel *Channel) Watch(ctx context.Context) (event.Subscription, error) {
+	watcher := channel.initProposal.Contract.Client.Events
+	if watcher == nil {
+		return nil, ErrNoEventWatcher
+	}
+
+	opts := &bind.WatchOpts{Context: ctx}
+	ids := [][32]byte{channel.c.Id}
+
+	deposited := make(chan *nitro.NitroAdjudicatorDeposited)
+	depositedSub, err := watcher.WatchDeposited(opts, deposited, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make(chan *nitro.NitroAdjudicatorChallengeRegistered)
+	registeredSub, err := watcher.WatchChallengeRegistered(opts, registered, ids)
+	if err != nil {
+		depositedSub.Unsubscribe()
+		return nil, err
+	}
+
+	concluded := make(chan *nitro.NitroAdjudicatorConcluded)
+	concludedSub, err := watcher.WatchConcluded(opts, concluded, ids)
+	if err != nil {
+		depositedSub.Unsubscribe()
+		registeredSub.Unsubscribe()
+		return nil, err
+	}
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer depositedSub.Unsubscribe()
+		defer registeredSub.Unsubscribe()
+		defer concludedSub.Unsubscribe()
+
+		for {
+			select {
+			case e := <-deposited:
+				channel.onDeposited(e)
+			case e := <-registered:
+				channel.publish(ChallengeRegistered{
+					channelEvent:  channelEvent{channel.c.Id},
+					TurnNumRecord: e.TurnNumRecord.Uint64(),
+					FinalizesAt:   e.FinalizesAt.Uint64(),
+				})
+			case e := <-concluded:
+				channel.publish(Concluded{channelEvent: channelEvent{channel.c.Id}, FinalizesAt: e.FinalizesAt.Uint64()})
+			case err := <-depositedSub.Err():
+				return err
+			case err := <-registeredSub.Err():
+				return err
+			case err := <-concludedSub.Err():
+				return err
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// onDeposited publishes FundingComplete once the deposit makes holdings of the channel asset sufficient.
+func (channel *Channel) onDeposited(e *nitro.NitroAdjudicatorDeposited) {
+	if e.Asset != channel.initProposal.Contract.AssetAddress {
+		return
+	}
+
+	channel.mu.RLock()
+	total := channel.c.PreFundState().Outcome.TotalAllocated()[e.Asset]
+	channel.mu.RUnlock()
+	if total == nil {
+		total = big.NewInt(0)
+	}
+
+	before := new(big.Int).Sub(e.DestinationHoldings, e.AmountDeposited)
+	if e.DestinationHoldings.Cmp(total) >= 0 && before.Cmp(total) < 0 {
+		channel.publish(FundingComplete{channelEvent: channelEvent{channel.c.Id}, Holdings: new(big.Int).Set(e.DestinationHoldings)})
+	}
+}
+
+// publish delivers the event to subscribers of the channel.
+func (channel *Channel) publish(e Event) {
+	channel.subsMu.Lock()
+	defer channel.subsMu.Unlock()
+
+	for sub := range channel.subscriptions {
+		sub.push(e)
+	}
+}
+
+// supportedTurnNum returns turn number of the latest supported state, false if no state is supported.
+// It must be called with the channel lock held.
+func (channel *Channel) supportedTurnNum() (uint64, bool) {
+	supported, err := channel.c.LatestSupportedState()
+	if err != nil {
+		return 0, false
+	}
+
+	return supported.TurnNum, true
+}
+
+// publishSupported publishes StateSupported if the latest supported state advanced from the given turn number.
+// It must be called with the channel lock held.
+func (channel *Channel) publishSupported(turnNum uint64, wasSupported bool) {
+	supported, err := channel.c.LatestSupportedState()
+	if err != nil || (wasSupported && supported.TurnNum <= turnNum) {
+		return
+	}
+
+	channel.publish(StateSupported{channelEvent: channelEvent{channel.c.Id}, State: cloneState(supported)})
+}
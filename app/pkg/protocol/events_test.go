@@ -0,0 +1,180 @@
+package protocol
+
+import (
+	"app/pkg/nitro"
+	"app/pkg/nitro/mock"
+	"app/pkg/nitro/simulated"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextEvent returns the next event of the subscription, the test fails if no event is delivered.
+func nextEvent(t *testing.T, sub *Subscription) Event {
+	select {
+	case e := <-sub.Events():
+		return e
+	case <-time.After(time.Second):
+		require.FailNow(t, "event isn't delivered")
+		return nil
+	}
+}
+
+// noEvent checks that no event is delivered to the subscription.
+func noEvent(t *testing.T, sub *Subscription) {
+	select {
+	case e := <-sub.Events():
+		assert.Failf(t, "unexpected event", "%#v", e)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// watchedChannel returns channel with the mock adjudicator watched for events and subscription to its events.
+// The channel isn't funded.
+func watchedChannel(t *testing.T, lockedAmounts ...int64) (testChannel, *Subscription) {
+	tc := newTestParticipants(t, lockedAmounts...)
+	tc.adjudicator = mock.NewAdjudicator(simulated.ChainID)
+	tc.init(t, nitro.Client{Adjudicator: tc.adjudicator, ChainID: simulated.ChainID, Chain: tc.adjudicator, Events: tc.adjudicator})
+
+	sub := tc.channel.Subscribe()
+	t.Cleanup(sub.Unsubscribe)
+	watch, err := tc.channel.Watch(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(watch.Unsubscribe)
+
+	return tc, sub
+}
+
+func TestLocalEvents(t *testing.T) {
+	t.Run("state supported", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		sub := tc.channel.Subscribe()
+		defer sub.Unsubscribe()
+
+		tc.trade(t, 1, false)
+
+		e := nextEvent(t, sub)
+		require.IsType(t, StateSupported{}, e)
+		assert.Equal(t, tc.channel.ID(), e.ChannelID())
+		assert.Equal(t, tc.channel.c.PostFundState().TurnNum+1, e.(StateSupported).State.TurnNum)
+		noEvent(t, sub)
+	})
+
+	t.Run("proposal received", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		counterparty, err := InitChannel(tc.channel.initProposal, 1)
+		require.NoError(t, err)
+		for _, s := range []state.State{tc.channel.c.PreFundState(), tc.channel.c.PostFundState()} {
+			for _, key := range tc.keys {
+				require.NoError(t, counterparty.AddSignature(&s, signatureOf(t, s, key)))
+			}
+		}
+
+		sub := counterparty.Subscribe()
+		defer sub.Unsubscribe()
+
+		tc.trade(t, 1, false)
+		s := tc.channel.CurrentState()
+		for _, key := range tc.keys {
+			require.NoError(t, counterparty.AddSignature(&s, signatureOf(t, s, key)))
+		}
+
+		e := nextEvent(t, sub)
+		require.IsType(t, ProposalReceived{}, e)
+		assert.True(t, s.Equal(e.(ProposalReceived).State))
+
+		e = nextEvent(t, sub)
+		require.IsType(t, StateSupported{}, e)
+		assert.True(t, s.Equal(e.(StateSupported).State))
+		noEvent(t, sub)
+	})
+
+	t.Run("unsubscribe closes events", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		sub := tc.channel.Subscribe()
+		sub.Unsubscribe()
+		sub.Unsubscribe()
+
+		tc.trade(t, 1, false)
+		_, ok := <-sub.Events()
+		assert.False(t, ok)
+	})
+}
+
+func TestChainEvents(t *testing.T) {
+	t.Run("funding complete and concluded", func(t *testing.T) {
+		tc, sub := watchedChannel(t, 5, 3)
+
+		for _, p := range tc.participants {
+			_, err := tc.channel.FundChannel(context.Background(), p, tc.keys[p.Index])
+			require.NoError(t, err)
+		}
+		e := nextEvent(t, sub)
+		require.IsType(t, FundingComplete{}, e)
+		assert.Equal(t, big.NewInt(8), e.(FundingComplete).Holdings)
+
+		for _, key := range tc.keys {
+			_, err := tc.channel.ApproveChannelFunding(key)
+			require.NoError(t, err)
+		}
+		require.IsType(t, StateSupported{}, nextEvent(t, sub))
+
+		signatures := tc.trade(t, 1, true)
+		require.IsType(t, StateSupported{}, nextEvent(t, sub))
+
+		_, err := tc.channel.Finalize(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		require.NoError(t, err)
+		e = nextEvent(t, sub)
+		require.IsType(t, Concluded{}, e)
+		assert.Equal(t, tc.adjudicator.Time(), e.(Concluded).FinalizesAt)
+		noEvent(t, sub)
+	})
+
+	t.Run("challenge registered", func(t *testing.T) {
+		tc, sub := watchedChannel(t, 5, 3)
+		tc.fund(t, mined, tc.participants...)
+		signatures := tc.trade(t, 1, false)
+		for i := 0; i < 3; i++ {
+			nextEvent(t, sub)
+		}
+
+		tc.challenge(t, 1, signatures)
+		e := nextEvent(t, sub)
+		require.IsType(t, ChallengeRegistered{}, e)
+		assert.Equal(t, tc.channel.CurrentState().TurnNum, e.(ChallengeRegistered).TurnNumRecord)
+	})
+
+	t.Run("other channels are not watched", func(t *testing.T) {
+		tc, sub := watchedChannel(t, 5, 3)
+
+		opts := tc.transactOpts(t, 0)
+		opts.Value = big.NewInt(8)
+		_, err := tc.adjudicator.Deposit(opts, common.Address{}, common.Hash{0xff}, big.NewInt(0), big.NewInt(8))
+		require.NoError(t, err)
+		noEvent(t, sub)
+	})
+
+	t.Run("watcher is required", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		tc.channel.initProposal.Contract.Client.Events = nil
+
+		_, err := tc.channel.Watch(context.Background())
+		assert.ErrorIs(t, err, ErrNoEventWatcher)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		ctx, cancel := context.WithCancel(context.Background())
+		watch, err := tc.channel.Watch(ctx)
+		require.NoError(t, err)
+
+		cancel()
+		assert.ErrorIs(t, <-watch.Err(), context.Canceled)
+	})
+}
@@ -0,0 +1,263 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	ntypes "github.com/statechannels/go-nitro/types"
+)
+
+var (
+	ErrEvidenceChannelID = errors.New("evidence: channel id doesn't match fixed part")
+	ErrEvidenceStateHash = errors.New("evidence: state hash doesn't match state")
+	ErrEvidenceSignature = errors.New("evidence: invalid signature")
+	ErrEvidenceEmpty     = errors.New("evidence: no signed states")
+	ErrEvidenceUnsigned  = errors.New("evidence: state isn't signed")
+)
+
+// Evidence is a self-contained bundle of signed states of the channel, it is verified without a node.
+type Evidence struct {
+	ChannelID common.Hash       `json:"channelId"`
+	FixedPart EvidenceFixedPart `json:"fixedPart"`
+	States    []EvidenceState   `json:"states"`
+}
+
+// EvidenceFixedPart is the part of channel states which doesn't change.
+type EvidenceFixedPart struct {
+	ChainID           *big.Int         `json:"chainId"`
+	Participants      []common.Address `json:"participants"`
+	ChannelNonce      *big.Int         `json:"channelNonce"`
+	AppDefinition     common.Address   `json:"appDefinition"`
+	ChallengeDuration *big.Int         `json:"challengeDuration"`
+}
+
+// EvidenceState is the variable part of the signed state with its hash and signatures.
+// Outcome is ABI encoded as it is submitted to the adjudicator.
+type EvidenceState struct {
+	TurnNum    uint64              `json:"turnNum"`
+	IsFinal    bool                `json:"isFinal"`
+	AppData    hexutil.Bytes       `json:"appData"`
+	Outcome    hexutil.Bytes       `json:"outcome"`
+	StateHash  common.Hash         `json:"stateHash"`
+	Signatures []EvidenceSignature `json:"signatures"`
+}
+
+// EvidenceSignature is a signature of the state in [R || S || V] format.
+type EvidenceSignature struct {
+	Signer    common.Address `json:"signer"`
+	Signature hexutil.Bytes  `json:"signature"`
+}
+
+// NewEvidence returns evidence without states of the channel with the fixed part.
+func NewEvidence(fixedPart state.FixedPart) (*Evidence, error) {
+	id, err := fixedPart.ChannelId()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Evidence{
+		ChannelID: common.Hash(id),
+		FixedPart: EvidenceFixedPart{
+			ChainID:           new(big.Int).Set(fixedPart.ChainId),
+			Participants:      append([]common.Address(nil), fixedPart.Participants...),
+			ChannelNonce:      new(big.Int).Set(fixedPart.ChannelNonce),
+			AppDefinition:     fixedPart.AppDefinition,
+			ChallengeDuration: new(big.Int).Set(fixedPart.ChallengeDuration),
+		},
+	}, nil
+}
+
+// AddState adds the state with its signatures to the evidence, states are kept ordered by turn number.
+// An error is thrown if the state belongs to another channel or signature isn't made by a participant.
+func (e *Evidence) AddState(s state.State, signatures ...state.Signature) error {
+	id, err := s.ChannelId()
+	if err != nil {
+		return err
+	}
+
+	if common.Hash(id) != e.ChannelID {
+		return ErrEvidenceChannelID
+	}
+
+	hash, err := s.Hash()
+	if err != nil {
+		return err
+	}
+
+	encodedOutcome, err := s.Outcome.Encode()
+	if err != nil {
+		return err
+	}
+
+	evidenceState := EvidenceState{
+		TurnNum:   s.TurnNum,
+		IsFinal:   s.IsFinal,
+		AppData:   append(hexutil.Bytes{}, s.AppData...),
+		Outcome:   hexutil.Bytes(encodedOutcome),
+		StateHash: common.Hash(hash),
+	}
+
+	for _, signature := range signatures {
+		signer, err := s.RecoverSigner(signature)
+		if err != nil {
+			return err
+		}
+
+		if e.participantIndex(signer) < 0 {
+			return fmt.Errorf("%w: %s is not a participant", ErrEvidenceSignature, signer)
+		}
+
+		evidenceState.Signatures = append(evidenceState.Signatures, EvidenceSignature{Signer: signer, Signature: EncodeSignature(signature)})
+	}
+
+	e.States = append(e.States, evidenceState)
+	sort.SliceStable(e.States, func(i, j int) bool {
+		return e.States[i].TurnNum < e.States[j].TurnNum
+	})
+
+	return nil
+}
+
+// Verify recomputes channel ID and state hashes and checks that every signature is made by its signer,
+// a participant of the channel. Evidence must contain at least one state, and every state must be signed
+// at least once with a single signature per participant.
+func (e *Evidence) Verify() error {
+	if e.FixedPart.ChainID == nil || e.FixedPart.ChannelNonce == nil || e.FixedPart.ChallengeDuration == nil {
+		return ErrEvidenceChannelID
+	}
+
+	fixedPart := e.fixedPart()
+	id, err := fixedPart.ChannelId()
+	if err != nil {
+		return err
+	}
+
+	if common.Hash(id) != e.ChannelID {
+		return ErrEvidenceChannelID
+	}
+
+	if len(e.States) == 0 {
+		return ErrEvidenceEmpty
+	}
+
+	for _, evidenceState := range e.States {
+		if len(evidenceState.Signatures) == 0 {
+			return fmt.Errorf("%w: turn %d", ErrEvidenceUnsigned, evidenceState.TurnNum)
+		}
+
+		s, err := evidenceState.state(fixedPart)
+		if err != nil {
+			return err
+		}
+
+		hash, err := s.Hash()
+		if err != nil {
+			return err
+		}
+
+		if common.Hash(hash) != evidenceState.StateHash {
+			return fmt.Errorf("%w: turn %d", ErrEvidenceStateHash, evidenceState.TurnNum)
+		}
+
+		signers := make(map[common.Address]struct{})
+		for _, signature := range evidenceState.Signatures {
+			decoded, err := DecodeSignature(signature.Signature)
+			if err != nil || e.participantIndex(signature.Signer) < 0 {
+				return fmt.Errorf("%w: turn %d signer %s", ErrEvidenceSignature, evidenceState.TurnNum, signature.Signer)
+			}
+
+			if _, found := signers[signature.Signer]; found {
+				return fmt.Errorf("%w: turn %d signer %s signed twice", ErrEvidenceSignature, evidenceState.TurnNum, signature.Signer)
+			}
+			signers[signature.Signer] = struct{}{}
+
+			signer, err := s.RecoverSigner(decoded)
+			if err != nil || signer != signature.Signer {
+				return fmt.Errorf("%w: turn %d signer %s", ErrEvidenceSignature, evidenceState.TurnNum, signature.Signer)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Evidence returns evidence of the channel with all signed states including equivocated ones.
+func (channel *Channel) Evidence() (*Evidence, error) {
+	channel.mu.RLock()
+	defer channel.mu.RUnlock()
+
+	evidence, err := NewEvidence(channel.c.FixedPart)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, signed := range channel.c.SignedStateForTurnNum {
+		var signatures []state.Signature
+		for i := range channel.c.Participants {
+			signature, err := signed.GetParticipantSignature(uint(i))
+			if err == nil {
+				signatures = append(signatures, signature)
+			}
+		}
+
+		if len(signatures) == 0 {
+			continue
+		}
+
+		if err := evidence.AddState(signed.State(), signatures...); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, equivocation := range channel.equivocations {
+		conflicting := equivocation.States[1]
+		if err := evidence.AddState(conflicting.State, conflicting.Signature); err != nil {
+			return nil, err
+		}
+	}
+
+	return evidence, nil
+}
+
+// fixedPart returns fixed part of the channel states.
+func (e *Evidence) fixedPart() state.FixedPart {
+	return state.FixedPart{
+		ChainId:           e.FixedPart.ChainID,
+		Participants:      e.FixedPart.Participants,
+		ChannelNonce:      e.FixedPart.ChannelNonce,
+		AppDefinition:     e.FixedPart.AppDefinition,
+		ChallengeDuration: e.FixedPart.ChallengeDuration,
+	}
+}
+
+// participantIndex returns index of the participant with the address, -1 if address isn't a participant.
+func (e *Evidence) participantIndex(address common.Address) int {
+	for i, participant := range e.FixedPart.Participants {
+		if participant == address {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// state returns state built from the fixed part and the variable part of the evidence state.
+func (es EvidenceState) state(fixedPart state.FixedPart) (state.State, error) {
+	exit, err := outcome.Decode(ntypes.Bytes(es.Outcome))
+	if err != nil {
+		return state.State{}, err
+	}
+
+	return state.StateFromFixedAndVariablePart(fixedPart, state.VariablePart{
+		AppData: []byte(es.AppData),
+		Outcome: exit,
+		TurnNum: es.TurnNum,
+		IsFinal: es.IsFinal,
+	}), nil
+}
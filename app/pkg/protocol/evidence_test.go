@@ -0,0 +1,111 @@
+package protocol
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportedEvidence returns evidence of the channel decoded from JSON like it is read by the verifier.
+func exportedEvidence(t *testing.T, ch *Channel) *Evidence {
+	evidence, err := ch.Evidence()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(evidence)
+	require.NoError(t, err)
+
+	var decoded Evidence
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	return &decoded
+}
+
+func TestEvidence(t *testing.T) {
+	tc := getMockChannel(t, 5, 3)
+	tc.trade(t, 1, false)
+	tc.trade(t, 1, true)
+
+	evidence := exportedEvidence(t, tc.channel)
+	assert.Equal(t, common.Hash(tc.channel.ID()), evidence.ChannelID)
+	assert.Equal(t, tc.channel.c.Participants, evidence.FixedPart.Participants)
+	require.Len(t, evidence.States, 4)
+	for i, s := range evidence.States {
+		assert.Equal(t, uint64(i), s.TurnNum)
+		assert.Len(t, s.Signatures, 2)
+	}
+	assert.True(t, evidence.States[3].IsFinal)
+
+	current := tc.channel.CurrentState()
+	hash, err := current.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash(hash), evidence.States[3].StateHash)
+
+	assert.NoError(t, evidence.Verify())
+}
+
+func TestEvidenceEquivocation(t *testing.T) {
+	tc := getMockChannel(t, 5, 3)
+	first, second := nextTurnState(tc.channel, 1), nextTurnState(tc.channel, 2)
+	require.NoError(t, tc.channel.AddSignature(&first, signatureOf(t, first, tc.keys[1])))
+	require.ErrorIs(t, tc.channel.AddSignature(&second, signatureOf(t, second, tc.keys[1])), ErrEquivocation)
+
+	evidence := exportedEvidence(t, tc.channel)
+	require.Len(t, evidence.States, 4)
+	assert.Equal(t, evidence.States[2].TurnNum, evidence.States[3].TurnNum)
+	assert.NotEqual(t, evidence.States[2].StateHash, evidence.States[3].StateHash)
+	assert.NoError(t, evidence.Verify())
+}
+
+func TestVerifyEvidence(t *testing.T) {
+	tc := getMockChannel(t, 5, 3)
+	tc.trade(t, 1, false)
+
+	cases := []struct {
+		name   string
+		tamper func(e *Evidence)
+		err    error
+	}{
+		{"channel nonce", func(e *Evidence) { e.FixedPart.ChannelNonce = big.NewInt(1) }, ErrEvidenceChannelID},
+		{"missing fixed part", func(e *Evidence) { e.FixedPart.ChainID = nil }, ErrEvidenceChannelID},
+		{"turn number", func(e *Evidence) { e.States[2].TurnNum++ }, ErrEvidenceStateHash},
+		{"final flag", func(e *Evidence) { e.States[2].IsFinal = true }, ErrEvidenceStateHash},
+		{"state hash", func(e *Evidence) { e.States[2].StateHash = common.Hash{} }, ErrEvidenceStateHash},
+		{"signer", func(e *Evidence) {
+			e.States[2].Signatures[0].Signer = e.States[2].Signatures[1].Signer
+		}, ErrEvidenceSignature},
+		{"signature of another state", func(e *Evidence) {
+			e.States[2].Signatures[0].Signature = e.States[1].Signatures[0].Signature
+		}, ErrEvidenceSignature},
+		{"truncated signature", func(e *Evidence) {
+			e.States[2].Signatures[0].Signature = e.States[2].Signatures[0].Signature[:64]
+		}, ErrEvidenceSignature},
+		{"not a participant", func(e *Evidence) {
+			e.States[2].Signatures[0].Signer = common.HexToAddress("0x01")
+		}, ErrEvidenceSignature},
+		{"duplicate signer", func(e *Evidence) {
+			e.States[2].Signatures[1] = e.States[2].Signatures[0]
+		}, ErrEvidenceSignature},
+		{"unsigned state", func(e *Evidence) { e.States[2].Signatures = nil }, ErrEvidenceUnsigned},
+		{"no states", func(e *Evidence) { e.States = nil }, ErrEvidenceEmpty},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evidence := exportedEvidence(t, tc.channel)
+			c.tamper(evidence)
+			assert.ErrorIs(t, evidence.Verify(), c.err)
+		})
+	}
+
+	t.Run("state of another channel", func(t *testing.T) {
+		other := getMockChannel(t, 5, 3)
+		evidence, err := tc.channel.Evidence()
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, evidence.AddState(other.channel.CurrentState()), ErrEvidenceChannelID)
+	})
+}
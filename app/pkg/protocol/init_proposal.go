@@ -1,19 +1,33 @@
 package protocol
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	st "github.com/statechannels/go-nitro/channel/state"
 	"github.com/statechannels/go-nitro/channel/state/outcome"
 )
 
+var (
+	ErrDuplicateParticipant = errors.New("proposal: duplicate participant address")
+	ErrInvalidLockedAmount  = errors.New("proposal: locked amount must be set and not negative")
+	ErrParticipantIndex     = errors.New("proposal: participant index doesn't match its position")
+	ErrNoFunds              = errors.New("proposal: participants lock no funds")
+)
+
 // InitProposal represents information about initial state, contract, participants.
+// Codec is optional app codec of the channel, liabilities app is used if it isn't set.
+// TurnTaking enables ForceMove turn rules: participant turnNum mod N proposes the state and signs it first.
 type InitProposal struct {
 	Participants []*Participant
 	State        *st.State
 	Contract     *Contract
 	ChannelNonce *big.Int
+	Codec        AppCodec
+	TurnTaking   bool
 }
 
 // NewInitProposal returns InitProposal object from income params.
@@ -32,7 +46,14 @@ func NewInitProposal(p *Participant, contract *Contract) *InitProposal {
 }
 
 // AddParticipant adds participant into proposed state and participant array.
-func (ip *InitProposal) AddParticipant(p *Participant) {
+// An error is thrown if participant address is already added, locked amount is missing or negative
+// or participant index isn't the position of the participant. Receiver-only participant locks zero amount.
+func (ip *InitProposal) AddParticipant(p *Participant) error {
+	err := validateParticipant(ip.Participants, p, uint(len(ip.Participants)))
+	if err != nil {
+		return err
+	}
+
 	ip.Participants = append(ip.Participants, p)
 
 	ip.State.Participants = append(ip.State.Participants, p.Address)
@@ -41,4 +62,51 @@ func (ip *InitProposal) AddParticipant(p *Participant) {
 			Destination: p.Destination,
 			Amount:      p.LockedAmount,
 		})
+
+	return nil
+}
+
+// Validate returns an error if participants of the proposal are invalid or none of them locks funds.
+func (ip *InitProposal) Validate() error {
+	total := big.NewInt(0)
+	for i, p := range ip.Participants {
+		err := validateParticipant(ip.Participants[:i], p, uint(i))
+		if err != nil {
+			return err
+		}
+		total.Add(total, p.LockedAmount)
+	}
+
+	if total.Sign() == 0 {
+		return ErrNoFunds
+	}
+
+	return nil
+}
+
+// SetApp sets app definition and app codec of the channel, it must be called before channel initialization.
+func (ip *InitProposal) SetApp(appDefinition common.Address, codec AppCodec) {
+	ip.State.AppDefinition = appDefinition
+	ip.Codec = codec
+}
+
+// validateParticipant checks participant added at the position after participants.
+// Zero locked amount is allowed: receiver-only participants, e.g. payee of a payment channel or of a hashlocked swap,
+// lock no funds and only receive allocations. Proposal where no participant locks funds is rejected by Validate.
+func validateParticipant(participants []*Participant, p *Participant, position uint) error {
+	if p.LockedAmount == nil || p.LockedAmount.Sign() < 0 {
+		return fmt.Errorf("%w: participant %s", ErrInvalidLockedAmount, p.Address)
+	}
+
+	if p.Index != position {
+		return fmt.Errorf("%w: participant %s has index %d at position %d", ErrParticipantIndex, p.Address, p.Index, position)
+	}
+
+	for _, added := range participants {
+		if added.Address == p.Address {
+			return fmt.Errorf("%w: %s", ErrDuplicateParticipant, p.Address)
+		}
+	}
+
+	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/statechannels/go-nitro/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInitProposal(t *testing.T) {
@@ -21,15 +22,60 @@ func TestInitProposal(t *testing.T) {
 }
 
 func TestAddParticipant(t *testing.T) {
-	participant1 := NewParticipant(common.HexToAddress("0x01"), types.Destination(common.HexToHash("0x01")), uint(1), big.NewInt(2))
-	participant2 := NewParticipant(common.HexToAddress("0x01"), types.Destination(common.HexToHash("0x01")), uint(1), big.NewInt(2))
+	participant1 := NewParticipant(common.HexToAddress("0x01"), types.Destination(common.HexToHash("0x01")), uint(0), big.NewInt(2))
+	participant2 := NewParticipant(common.HexToAddress("0x02"), types.Destination(common.HexToHash("0x02")), uint(1), big.NewInt(2))
 
 	contract := NewContract(nitro.Client{}, common.HexToAddress("0x"))
 	proposal := NewInitProposal(participant1, contract)
 	assert.NotEmpty(t, proposal)
 
-	proposal.AddParticipant(participant2)
+	assert.NoError(t, proposal.AddParticipant(participant2))
 	assert.Equal(t, 2, len(proposal.Participants))
 	assert.Equal(t, []*Participant{participant1, participant2}, proposal.Participants)
 	assert.Equal(t, []common.Address{participant1.Address, participant2.Address}, proposal.State.Participants)
+
+	t.Run("invalid participants", func(t *testing.T) {
+		cases := []struct {
+			name        string
+			participant *Participant
+			err         error
+		}{
+			{"duplicate address", NewParticipant(participant1.Address, participant1.Destination, 2, big.NewInt(1)), ErrDuplicateParticipant},
+			{"index doesn't match position", NewParticipant(common.HexToAddress("0x03"), participant1.Destination, 1, big.NewInt(1)), ErrParticipantIndex},
+			{"negative amount", NewParticipant(common.HexToAddress("0x03"), participant1.Destination, 2, big.NewInt(-1)), ErrInvalidLockedAmount},
+			{"missing amount", NewParticipant(common.HexToAddress("0x03"), participant1.Destination, 2, nil), ErrInvalidLockedAmount},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				assert.ErrorIs(t, proposal.AddParticipant(c.participant), c.err)
+				assert.Len(t, proposal.Participants, 2)
+				assert.Len(t, proposal.State.Outcome[0].Allocations, 2)
+			})
+		}
+	})
+}
+
+func TestValidateInitProposal(t *testing.T) {
+	contract := NewContract(nitro.Client{}, common.HexToAddress("0x"))
+
+	t.Run("receiver-only participant locks no funds", func(t *testing.T) {
+		proposal := NewInitProposal(NewParticipant(common.HexToAddress("0x01"), types.Destination(common.HexToHash("0x01")), 0, big.NewInt(2)), contract)
+		require.NoError(t, proposal.AddParticipant(NewParticipant(common.HexToAddress("0x02"), types.Destination(common.HexToHash("0x02")), 1, big.NewInt(0))))
+
+		assert.NoError(t, proposal.Validate())
+	})
+
+	t.Run("no participant locks funds", func(t *testing.T) {
+		proposal := NewInitProposal(NewParticipant(common.HexToAddress("0x01"), types.Destination(common.HexToHash("0x01")), 0, big.NewInt(0)), contract)
+		require.NoError(t, proposal.AddParticipant(NewParticipant(common.HexToAddress("0x02"), types.Destination(common.HexToHash("0x02")), 1, big.NewInt(0))))
+
+		assert.ErrorIs(t, proposal.Validate(), ErrNoFunds)
+	})
+
+	t.Run("index of the initiator", func(t *testing.T) {
+		proposal := NewInitProposal(NewParticipant(common.HexToAddress("0x01"), types.Destination(common.HexToHash("0x01")), 1, big.NewInt(2)), contract)
+
+		assert.ErrorIs(t, proposal.Validate(), ErrParticipantIndex)
+	})
 }
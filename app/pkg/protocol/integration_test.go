@@ -0,0 +1,341 @@
+package protocol
+
+import (
+	"app/pkg/nitro"
+	"app/pkg/nitro/mock"
+	"app/pkg/nitro/simulated"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	ecrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/crypto"
+	ntypes "github.com/statechannels/go-nitro/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testChannel is a funded channel between participants on the simulated backend or with the mock adjudicator.
+type testChannel struct {
+	backend      *simulated.Backend
+	adjudicator  *mock.Adjudicator
+	channel      *Channel
+	participants []*Participant
+	keys         [][]byte
+}
+
+// transferCodec is the codec of test channels which move funds directly in the outcome of non-final states.
+// App data is decoded as liabilities, but unlike LiabilitiesCodec outcome isn't checked against them.
+type transferCodec struct {
+	liabilities LiabilitiesCodec
+}
+
+func (c transferCodec) Decode(appData []byte) (interface{}, error) {
+	return c.liabilities.Decode(appData)
+}
+
+func (c transferCodec) Encode(app interface{}) ([]byte, error) {
+	return c.liabilities.Encode(app)
+}
+
+func (c transferCodec) ValidTransition(from, to interface{}) error {
+	return c.liabilities.ValidTransition(from, to)
+}
+
+// getSimulatedChannel opens channel on the simulated backend, every participant deposits locked amount.
+func getSimulatedChannel(t *testing.T, lockedAmounts ...int64) testChannel {
+	tc := newTestParticipants(t, lockedAmounts...)
+
+	var accounts []common.Address
+	for _, p := range tc.participants {
+		accounts = append(accounts, p.Address)
+	}
+	tc.backend = newTestBackend(t, accounts...)
+	tc.init(t, tc.backend.Client())
+	tc.fund(t, tc.backend.Mine, tc.participants...)
+
+	return tc
+}
+
+// newTestBackend returns simulated backend with funded accounts closed at the end of the test.
+func newTestBackend(t *testing.T, accounts ...common.Address) *simulated.Backend {
+	backend, err := simulated.NewBackend(accounts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { backend.Close() })
+
+	return backend
+}
+
+// getMockChannel opens channel with the mock adjudicator, every participant deposits locked amount.
+func getMockChannel(t *testing.T, lockedAmounts ...int64) testChannel {
+	tc := newTestParticipants(t, lockedAmounts...)
+	tc.adjudicator = mock.NewAdjudicator(simulated.ChainID)
+
+	tc.init(t, nitro.Client{Adjudicator: tc.adjudicator, ChainID: simulated.ChainID, Chain: tc.adjudicator, Events: tc.adjudicator})
+	tc.fund(t, mined, tc.participants...)
+
+	return tc
+}
+
+// newTestParticipants returns test channel participants with generated keys.
+func newTestParticipants(t *testing.T, lockedAmounts ...int64) testChannel {
+	var tc testChannel
+	for i, amount := range lockedAmounts {
+		key, address, err := simulated.Key()
+		require.NoError(t, err)
+
+		tc.participants = append(tc.participants, NewParticipant(address, ntypes.AddressToDestination(address), uint(i), big.NewInt(amount)))
+		tc.keys = append(tc.keys, key)
+	}
+
+	return tc
+}
+
+// init initializes channel with transfer codec and signs prefund state.
+func (tc *testChannel) init(t *testing.T, client nitro.Client) {
+	proposal := NewInitProposal(tc.participants[0], NewContract(client, common.Address{}))
+	for _, p := range tc.participants[1:] {
+		require.NoError(t, proposal.AddParticipant(p))
+	}
+	proposal.Codec = transferCodec{}
+
+	ch, err := InitChannel(proposal, 0)
+	require.NoError(t, err)
+	for _, key := range tc.keys {
+		_, err := ch.ApproveInitChannel(key)
+		require.NoError(t, err)
+	}
+	tc.channel = ch
+}
+
+// fund deposits locked amounts of the participants and signs postfund state,
+// funding transactions are mined with mine.
+func (tc *testChannel) fund(t *testing.T, mine func(*types.Transaction) (*types.Receipt, error), participants ...*Participant) {
+	for _, p := range participants {
+		expectedHoldings, err := tc.channel.CheckHoldings(context.Background())
+		require.NoError(t, err)
+
+		tx, err := tc.channel.FundChannel(context.Background(), p, tc.keys[p.Index])
+		require.NoError(t, err)
+		_, err = mine(tx)
+		require.NoError(t, err)
+
+		holdings, err := tc.channel.CheckHoldings(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, expectedHoldings.Add(expectedHoldings, p.LockedAmount), holdings)
+	}
+
+	for _, key := range tc.keys {
+		_, err := tc.channel.ApproveChannelFunding(key)
+		require.NoError(t, err)
+	}
+}
+
+// mined is used to mine transactions of the mock adjudicator, their effects are already applied.
+func mined(tx *types.Transaction) (*types.Receipt, error) {
+	return &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash()}, nil
+}
+
+// trade moves amount from the first to the second participant and returns signatures of the new state.
+func (tc testChannel) trade(t *testing.T, amount int64, final bool) map[common.Address]state.Signature {
+	sp, err := tc.channel.ProposeState()
+	require.NoError(t, err)
+
+	exit := sp.Outcome()
+	allocations := exit[0].Allocations
+	allocations[0].Amount = new(big.Int).Sub(allocations[0].Amount, big.NewInt(amount))
+	allocations[1].Amount = new(big.Int).Add(allocations[1].Amount, big.NewInt(amount))
+	sp.SetOutcome(exit)
+	if final {
+		require.NoError(t, sp.SetFinal())
+	}
+
+	signatures := make(map[common.Address]state.Signature)
+	for i, key := range tc.keys {
+		signature, err := tc.channel.SignState(context.Background(), sp, key)
+		require.NoError(t, err)
+		signatures[tc.participants[i].Address] = signature
+	}
+
+	return signatures
+}
+
+// balances returns on-chain balances of participants.
+func (tc testChannel) balances(t *testing.T) []*big.Int {
+	var balances []*big.Int
+	for _, p := range tc.participants {
+		balance, err := tc.backend.Balance(p.Address)
+		require.NoError(t, err)
+		balances = append(balances, balance)
+	}
+
+	return balances
+}
+
+// challenger registers challenges, it is implemented by NitroAdjudicator binding and mock adjudicator.
+type challenger interface {
+	Challenge(opts *bind.TransactOpts, fixedPart nitro.IForceMoveFixedPart, largestTurnNum *big.Int, variableParts []nitro.IForceMoveAppVariablePart, isFinalCount uint8, sigs []nitro.IForceMoveSignature, whoSignedWhat []uint8, challengerSig nitro.IForceMoveSignature) (*types.Transaction, error)
+}
+
+// challenge registers challenge with the latest state of the channel signed by all participants.
+func challenge(t *testing.T, adjudicator challenger, opts *bind.TransactOpts, ch *Channel, privateKey []byte, signatures map[common.Address]state.Signature) (*types.Transaction, error) {
+	s := ch.lastState
+	params, err := buildConcludeParams(s, signatures)
+	require.NoError(t, err)
+
+	stateHash, err := s.Hash()
+	require.NoError(t, err)
+	bytes32, err := abi.NewType("bytes32", "", nil)
+	require.NoError(t, err)
+	str, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	message, err := abi.Arguments{{Type: bytes32}, {Type: str}}.Pack(stateHash, "forceMove")
+	require.NoError(t, err)
+
+	signature, err := crypto.SignEthereumMessage(ecrypto.Keccak256(message), privateKey)
+	require.NoError(t, err)
+	signer, err := crypto.RecoverEthereumMessageSigner(ecrypto.Keccak256(message), signature)
+	require.NoError(t, err)
+
+	var challengerSig nitro.IForceMoveSignature
+	for i, address := range s.Participants {
+		if address == signer {
+			challengerSig = forceMoveSignatures(s, map[common.Address]state.Signature{signer: signature})[i]
+		}
+	}
+
+	return adjudicator.Challenge(opts,
+		params.FixedPart,
+		big.NewInt(int64(s.TurnNum)),
+		[]nitro.IForceMoveAppVariablePart{{Outcome: params.OutcomeState, AppData: params.AppData}},
+		0,
+		params.Signatures,
+		params.WhoSignedWhat,
+		challengerSig,
+	)
+}
+
+// challenge registers challenge with the latest state signed by all participants.
+func (tc testChannel) challenge(t *testing.T, index int, signatures map[common.Address]state.Signature) {
+	if tc.adjudicator != nil {
+		_, err := challenge(t, tc.adjudicator, tc.transactOpts(t, index), tc.channel, tc.keys[index], signatures)
+		require.NoError(t, err)
+		return
+	}
+
+	tx, err := challenge(t, tc.backend.Adjudicator, tc.transactOpts(t, index), tc.channel, tc.keys[index], signatures)
+	require.NoError(t, err)
+	_, err = tc.backend.Mine(tx)
+	require.NoError(t, err)
+}
+
+// transactOpts returns transaction options of the participant.
+func (tc testChannel) transactOpts(t *testing.T, index int) *bind.TransactOpts {
+	key, err := ecrypto.ToECDSA(tc.keys[index])
+	require.NoError(t, err)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, simulated.ChainID)
+	require.NoError(t, err)
+
+	return opts
+}
+
+func TestSimulatedConclude(t *testing.T) {
+	tc := getSimulatedChannel(t, 5, 3)
+
+	tc.trade(t, 1, false)
+	signatures := tc.trade(t, 2, true)
+	before := tc.balances(t)
+
+	t.Run("non final state can't be concluded", func(t *testing.T) {
+		tc := getSimulatedChannel(t, 1, 1)
+		signatures := tc.trade(t, 1, false)
+
+		_, err := tc.channel.Conclude(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		assert.ErrorIs(t, err, ErrNotFinalState)
+	})
+
+	tx, err := tc.channel.Conclude(context.Background(), tc.participants[0], tc.keys[0], signatures)
+	require.NoError(t, err)
+	_, err = tc.backend.Mine(tx)
+	require.NoError(t, err)
+
+	holdings, err := tc.channel.CheckHoldings(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, holdings.Sign())
+
+	after := tc.balances(t)
+	assert.Equal(t, big.NewInt(6), new(big.Int).Sub(after[1], before[1]))
+	// first participant paid gas for conclude transaction
+	assert.True(t, after[0].Cmp(new(big.Int).Add(before[0], big.NewInt(2))) < 0)
+}
+
+func TestSimulatedChallenge(t *testing.T) {
+	tc := getSimulatedChannel(t, 5, 3)
+	signatures := tc.trade(t, 4, false)
+	tc.challenge(t, 1, signatures)
+
+	status, err := tc.channel.OnChainStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, nitro.Challenge, status.Mode)
+	assert.Equal(t, tc.channel.lastState.TurnNum, status.TurnNumRecord)
+	assert.True(t, status.FingerprintMatch)
+
+	t.Run("funds are locked until challenge expires", func(t *testing.T) {
+		_, err := tc.channel.WithdrawAfterFinalization(context.Background(), tc.participants[1], tc.keys[1])
+		assert.ErrorIs(t, err, ErrNotFinalized)
+	})
+
+	before := tc.balances(t)
+	require.NoError(t, tc.backend.IncreaseTime(time.Duration(tc.channel.lastState.ChallengeDuration.Int64()+1)*time.Second))
+
+	tx, err := tc.channel.WithdrawAfterFinalization(context.Background(), tc.participants[1], tc.keys[1])
+	require.NoError(t, err)
+	_, err = tc.backend.Mine(tx)
+	require.NoError(t, err)
+
+	after := tc.balances(t)
+	assert.Equal(t, big.NewInt(1), new(big.Int).Sub(after[0], before[0]))
+
+	holdings, err := tc.channel.CheckHoldings(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, holdings.Sign())
+}
+
+func TestSimulatedPayout(t *testing.T) {
+	tc := getSimulatedChannel(t, 5, 3)
+	signatures := tc.trade(t, 2, true)
+
+	tx, err := tc.channel.Finalize(context.Background(), tc.participants[0], tc.keys[0], signatures)
+	require.NoError(t, err)
+	_, err = tc.backend.Mine(tx)
+	require.NoError(t, err)
+
+	before := tc.balances(t)
+	tx, err = tc.channel.Payout(context.Background(), tc.participants[0], tc.keys[0], []uint{1})
+	require.NoError(t, err)
+	receipt, err := tc.backend.Mine(tx)
+	require.NoError(t, err)
+	tc.channel.ConfirmTransaction(receipt)
+
+	after := tc.balances(t)
+	assert.Equal(t, big.NewInt(5), new(big.Int).Sub(after[1], before[1]))
+
+	holdings, err := tc.channel.CheckHoldings(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(3), holdings)
+
+	tx, err = tc.channel.Payout(context.Background(), tc.participants[1], tc.keys[1], []uint{0})
+	require.NoError(t, err)
+	_, err = tc.backend.Mine(tx)
+	require.NoError(t, err)
+
+	holdings, err = tc.channel.CheckHoldings(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, holdings.Sign())
+}
@@ -0,0 +1,104 @@
+package protocol
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Lifecycle represents stage of the channel lifecycle.
+type Lifecycle int
+
+const (
+	// Opening channel waits for prefund signatures or funding of participants.
+	Opening Lifecycle = iota
+	// Funded channel is funded by all participants, no state has been supported after postfund state.
+	Funded
+	// Running channel has supported states after postfund state.
+	Running
+	// Concluding channel has supported final state, its funds aren't paid out yet.
+	Concluding
+	// Closing channel has submitted transaction which pays out its remaining funds, the transaction isn't confirmed yet.
+	Closing
+	// Closed channel has paid out its funds.
+	Closed
+)
+
+// String returns name of the lifecycle stage.
+func (l Lifecycle) String() string {
+	switch l {
+	case Opening:
+		return "Opening"
+	case Funded:
+		return "Funded"
+	case Running:
+		return "Running"
+	case Concluding:
+		return "Concluding"
+	case Closing:
+		return "Closing"
+	case Closed:
+		return "Closed"
+	default:
+		return "Unknown"
+	}
+}
+
+// Lifecycle returns current stage of the channel lifecycle.
+func (channel *Channel) Lifecycle() Lifecycle {
+	channel.mu.RLock()
+	defer channel.mu.RUnlock()
+
+	if channel.closed {
+		return Closed
+	}
+	if channel.closingTx != nil {
+		return Closing
+	}
+	if !channel.c.PostFundComplete() {
+		return Opening
+	}
+
+	supported, err := channel.c.LatestSupportedState()
+	switch {
+	case err != nil:
+		return Opening
+	case supported.IsFinal:
+		return Concluding
+	case supported.TurnNum > channel.c.PostFundState().TurnNum:
+		return Running
+	default:
+		return Funded
+	}
+}
+
+// ConfirmTransaction updates the channel with receipt of the transaction submitted by the channel.
+// Outcome left after payout is registered once the payout transaction succeeds.
+// Channel is closed once the transaction which pays out its remaining funds succeeds, if the transaction fails
+// the channel returns to the previous stage and funds can be paid out again. Receipts of other transactions are ignored.
+func (channel *Channel) ConfirmTransaction(receipt *types.Receipt) {
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+
+	succeeded := receipt.Status == types.ReceiptStatusSuccessful
+	if payout, found := channel.pendingPayouts[receipt.TxHash]; found {
+		delete(channel.pendingPayouts, receipt.TxHash)
+		if succeeded {
+			channel.registered = payout
+		}
+	}
+
+	if channel.closingTx == nil || *channel.closingTx != receipt.TxHash {
+		return
+	}
+
+	channel.closingTx = nil
+	channel.closed = succeeded
+}
+
+// closing records submitted transaction which pays out remaining funds of the channel.
+func (channel *Channel) closing(tx *types.Transaction) {
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+
+	hash := tx.Hash()
+	channel.closingTx = &hash
+}
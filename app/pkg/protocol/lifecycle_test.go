@@ -0,0 +1,81 @@
+package protocol
+
+import (
+	"app/pkg/nitro"
+	"app/pkg/nitro/mock"
+	"app/pkg/nitro/simulated"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycle(t *testing.T) {
+	tc := newTestParticipants(t, 5, 3)
+	tc.adjudicator = mock.NewAdjudicator(simulated.ChainID)
+	tc.init(t, nitro.Client{Adjudicator: tc.adjudicator, ChainID: simulated.ChainID, Chain: tc.adjudicator, Events: tc.adjudicator})
+	assert.Equal(t, Opening, tc.channel.Lifecycle())
+
+	tc.fund(t, mined, tc.participants...)
+	assert.Equal(t, Funded, tc.channel.Lifecycle())
+
+	tc.trade(t, 1, false)
+	assert.Equal(t, Running, tc.channel.Lifecycle())
+
+	signatures := tc.trade(t, 1, true)
+	assert.Equal(t, Concluding, tc.channel.Lifecycle())
+
+	tx, err := tc.channel.Conclude(context.Background(), tc.participants[0], tc.keys[0], signatures)
+	require.NoError(t, err)
+	assert.Equal(t, Closing, tc.channel.Lifecycle())
+
+	receipt, err := mined(tx)
+	require.NoError(t, err)
+	tc.channel.ConfirmTransaction(receipt)
+	assert.Equal(t, Closed, tc.channel.Lifecycle())
+	assert.Equal(t, "Closed", tc.channel.Lifecycle().String())
+}
+
+func TestConfirmTransaction(t *testing.T) {
+	t.Run("failed conclusion", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 1, true)
+
+		tx, err := tc.channel.Conclude(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		require.NoError(t, err)
+		assert.Equal(t, Closing, tc.channel.Lifecycle())
+
+		tc.channel.ConfirmTransaction(&types.Receipt{Status: types.ReceiptStatusFailed, TxHash: tx.Hash()})
+		assert.Equal(t, Concluding, tc.channel.Lifecycle())
+	})
+
+	t.Run("receipt of another transaction", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 1, true)
+
+		_, err := tc.channel.Conclude(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		require.NoError(t, err)
+
+		tc.channel.ConfirmTransaction(&types.Receipt{Status: types.ReceiptStatusSuccessful})
+		assert.Equal(t, Closing, tc.channel.Lifecycle())
+	})
+
+	t.Run("withdraw after finalization", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 2, false)
+		tc.challenge(t, 1, signatures)
+		tc.adjudicator.IncreaseTime(time.Duration(tc.channel.lastState.ChallengeDuration.Int64()) * time.Second)
+
+		tx, err := tc.channel.WithdrawAfterFinalization(context.Background(), tc.participants[1], tc.keys[1])
+		require.NoError(t, err)
+		assert.Equal(t, Closing, tc.channel.Lifecycle())
+
+		receipt, err := mined(tx)
+		require.NoError(t, err)
+		tc.channel.ConfirmTransaction(receipt)
+		assert.Equal(t, Closed, tc.channel.Lifecycle())
+	})
+}
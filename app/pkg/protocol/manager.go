@@ -0,0 +1,216 @@
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	ntypes "github.com/statechannels/go-nitro/types"
+)
+
+var (
+	ErrChannelNotFound  = errors.New("manager: channel not found")
+	ErrDuplicateChannel = errors.New("manager: channel is already managed")
+)
+
+// ChannelInfo represents channel managed by the ChannelManager.
+type ChannelInfo struct {
+	ID             ntypes.Destination
+	Counterparties []common.Address
+	Lifecycle      Lifecycle
+}
+
+// managedChannel guards operations of the channel.
+type managedChannel struct {
+	// lock is held by the operation in progress, it is a channel to make waiting cancellable.
+	lock           chan struct{}
+	channel        *Channel
+	counterparties []common.Address
+}
+
+// ChannelManager indexes channels by ID and counterparty and serializes operations of every channel.
+// Operations of different channels run concurrently.
+type ChannelManager struct {
+	mu             sync.RWMutex
+	channels       map[ntypes.Destination]*managedChannel
+	counterparties map[common.Address]map[ntypes.Destination]struct{}
+}
+
+// NewChannelManager returns a new ChannelManager without channels.
+func NewChannelManager() *ChannelManager {
+	return &ChannelManager{
+		channels:       make(map[ntypes.Destination]*managedChannel),
+		counterparties: make(map[common.Address]map[ntypes.Destination]struct{}),
+	}
+}
+
+// Open opens channel with init proposal on behalf of participant and adds it to the manager.
+// It returns identifier of the opened channel.
+func (m *ChannelManager) Open(initProposal *InitProposal, participantIndex uint) (ntypes.Destination, error) {
+	channel, err := InitChannel(initProposal, participantIndex)
+	if err != nil {
+		return ntypes.Destination{}, err
+	}
+
+	err = m.Add(channel)
+	if err != nil {
+		return ntypes.Destination{}, err
+	}
+
+	return channel.ID(), nil
+}
+
+// Add adds channel to the manager, participants other than channel owner are indexed as counterparties.
+func (m *ChannelManager) Add(channel *Channel) error {
+	var counterparties []common.Address
+	for i, address := range channel.c.Participants {
+		if uint(i) != channel.c.MyIndex {
+			counterparties = append(counterparties, address)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := channel.ID()
+	if _, found := m.channels[id]; found {
+		return ErrDuplicateChannel
+	}
+
+	m.channels[id] = &managedChannel{lock: make(chan struct{}, 1), channel: channel, counterparties: counterparties}
+	for _, address := range counterparties {
+		if m.counterparties[address] == nil {
+			m.counterparties[address] = make(map[ntypes.Destination]struct{})
+		}
+		m.counterparties[address][id] = struct{}{}
+	}
+
+	return nil
+}
+
+// Remove removes channel from the manager, operation of the channel in progress is completed.
+func (m *ChannelManager) Remove(id ntypes.Destination) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mc, found := m.channels[id]
+	if !found {
+		return ErrChannelNotFound
+	}
+
+	delete(m.channels, id)
+	for _, address := range mc.counterparties {
+		delete(m.counterparties[address], id)
+		if len(m.counterparties[address]) == 0 {
+			delete(m.counterparties, address)
+		}
+	}
+
+	return nil
+}
+
+// Do calls fn with the channel, calls for the same channel are serialized.
+// It returns context error if the context is done before the operation in progress is completed,
+// otherwise error returned by fn.
+func (m *ChannelManager) Do(ctx context.Context, id ntypes.Destination, fn func(*Channel) error) error {
+	mc, err := m.channel(id)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case mc.lock <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-mc.lock }()
+
+	return fn(mc.channel)
+}
+
+// Info returns information about the channel.
+func (m *ChannelManager) Info(id ntypes.Destination) (ChannelInfo, error) {
+	mc, err := m.channel(id)
+	if err != nil {
+		return ChannelInfo{}, err
+	}
+
+	return mc.info(), nil
+}
+
+// List returns channels in the given lifecycle stages ordered by ID, all channels are returned when no stage is given.
+func (m *ChannelManager) List(lifecycles ...Lifecycle) []ChannelInfo {
+	m.mu.RLock()
+	channels := make([]*managedChannel, 0, len(m.channels))
+	for _, mc := range m.channels {
+		channels = append(channels, mc)
+	}
+	m.mu.RUnlock()
+
+	return filterInfo(channels, lifecycles)
+}
+
+// ByCounterparty returns channels with the counterparty ordered by ID.
+func (m *ChannelManager) ByCounterparty(address common.Address, lifecycles ...Lifecycle) []ChannelInfo {
+	m.mu.RLock()
+	channels := make([]*managedChannel, 0, len(m.counterparties[address]))
+	for id := range m.counterparties[address] {
+		channels = append(channels, m.channels[id])
+	}
+	m.mu.RUnlock()
+
+	return filterInfo(channels, lifecycles)
+}
+
+// channel returns managed channel with the given id.
+func (m *ChannelManager) channel(id ntypes.Destination) (*managedChannel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	mc, found := m.channels[id]
+	if !found {
+		return nil, ErrChannelNotFound
+	}
+
+	return mc, nil
+}
+
+// info returns information about the channel.
+func (mc *managedChannel) info() ChannelInfo {
+	return ChannelInfo{
+		ID:             mc.channel.ID(),
+		Counterparties: append([]common.Address(nil), mc.counterparties...),
+		Lifecycle:      mc.channel.Lifecycle(),
+	}
+}
+
+// filterInfo returns information about channels in the given lifecycle stages ordered by ID.
+func filterInfo(channels []*managedChannel, lifecycles []Lifecycle) []ChannelInfo {
+	infos := make([]ChannelInfo, 0, len(channels))
+	for _, mc := range channels {
+		info := mc.info()
+		if len(lifecycles) == 0 || containsLifecycle(lifecycles, info.Lifecycle) {
+			infos = append(infos, info)
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return bytes.Compare(infos[i].ID.Bytes(), infos[j].ID.Bytes()) < 0
+	})
+
+	return infos
+}
+
+// containsLifecycle returns true if lifecycle is in the list.
+func containsLifecycle(lifecycles []Lifecycle, lifecycle Lifecycle) bool {
+	for _, l := range lifecycles {
+		if l == lifecycle {
+			return true
+		}
+	}
+
+	return false
+}
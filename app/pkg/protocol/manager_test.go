@@ -0,0 +1,180 @@
+package protocol
+
+import (
+	"app/pkg/nitro"
+	"app/pkg/nitro/mock"
+	"app/pkg/nitro/simulated"
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transfer moves amount from the first to the second participant, the state is signed by all keys.
+func transfer(ch *Channel, keys [][]byte, amount int64) error {
+	sp, err := ch.ProposeState()
+	if err != nil {
+		return err
+	}
+
+	exit := sp.Outcome()
+	allocations := exit[0].Allocations
+	allocations[0].Amount = new(big.Int).Sub(allocations[0].Amount, big.NewInt(amount))
+	allocations[1].Amount = new(big.Int).Add(allocations[1].Amount, big.NewInt(amount))
+	sp.SetOutcome(exit)
+
+	for _, key := range keys {
+		_, err := ch.SignState(context.Background(), sp, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestChannelManager(t *testing.T) {
+	first := getMockChannel(t, 5, 3)
+	second := getMockChannel(t, 2, 2)
+	manager := NewChannelManager()
+
+	require.NoError(t, manager.Add(first.channel))
+	require.NoError(t, manager.Add(second.channel))
+	assert.ErrorIs(t, manager.Add(first.channel), ErrDuplicateChannel)
+
+	t.Run("info", func(t *testing.T) {
+		info, err := manager.Info(first.channel.ID())
+		require.NoError(t, err)
+		assert.Equal(t, first.channel.ID(), info.ID)
+		assert.Equal(t, first.participants[1].Address, info.Counterparties[0])
+		assert.Len(t, info.Counterparties, 1)
+		assert.Equal(t, Funded, info.Lifecycle)
+	})
+
+	t.Run("by counterparty", func(t *testing.T) {
+		infos := manager.ByCounterparty(second.participants[1].Address)
+		require.Len(t, infos, 1)
+		assert.Equal(t, second.channel.ID(), infos[0].ID)
+
+		assert.Empty(t, manager.ByCounterparty(first.participants[0].Address))
+	})
+
+	t.Run("list by lifecycle", func(t *testing.T) {
+		err := manager.Do(context.Background(), first.channel.ID(), func(ch *Channel) error {
+			return transfer(ch, first.keys, 1)
+		})
+		require.NoError(t, err)
+
+		assert.Len(t, manager.List(), 2)
+		running := manager.List(Running)
+		require.Len(t, running, 1)
+		assert.Equal(t, first.channel.ID(), running[0].ID)
+		assert.Len(t, manager.List(Opening, Funded), 1)
+		assert.Empty(t, manager.List(Closed))
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		require.NoError(t, manager.Remove(second.channel.ID()))
+		assert.ErrorIs(t, manager.Remove(second.channel.ID()), ErrChannelNotFound)
+
+		_, err := manager.Info(second.channel.ID())
+		assert.ErrorIs(t, err, ErrChannelNotFound)
+		err = manager.Do(context.Background(), second.channel.ID(), func(*Channel) error { return nil })
+		assert.ErrorIs(t, err, ErrChannelNotFound)
+		assert.Empty(t, manager.ByCounterparty(second.participants[1].Address))
+		assert.Len(t, manager.List(), 1)
+	})
+}
+
+func TestChannelManagerOpen(t *testing.T) {
+	tc := newTestParticipants(t, 5, 3)
+	client := nitro.Client{Adjudicator: mock.NewAdjudicator(simulated.ChainID), ChainID: simulated.ChainID}
+	proposal := NewInitProposal(tc.participants[0], NewContract(client, common.Address{}))
+	require.NoError(t, proposal.AddParticipant(tc.participants[1]))
+
+	manager := NewChannelManager()
+	id, err := manager.Open(proposal, 1)
+	require.NoError(t, err)
+
+	infos := manager.ByCounterparty(tc.participants[0].Address, Opening)
+	require.Len(t, infos, 1)
+	assert.Equal(t, id, infos[0].ID)
+}
+
+func TestChannelManagerConcurrency(t *testing.T) {
+	manager := NewChannelManager()
+	var channels []testChannel
+	for i := 0; i < 4; i++ {
+		tc := getMockChannel(t, 100, 100)
+		require.NoError(t, manager.Add(tc.channel))
+		channels = append(channels, tc)
+	}
+
+	const transfers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, len(channels)*transfers)
+	for _, tc := range channels {
+		for i := 0; i < transfers; i++ {
+			wg.Add(1)
+			go func(tc testChannel) {
+				defer wg.Done()
+				errs <- manager.Do(context.Background(), tc.channel.ID(), func(ch *Channel) error {
+					return transfer(ch, tc.keys, 1)
+				})
+			}(tc)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				manager.List()
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	for _, tc := range channels {
+		err := manager.Do(context.Background(), tc.channel.ID(), func(ch *Channel) error {
+			supported, err := ch.c.LatestSupportedState()
+			if err != nil {
+				return err
+			}
+
+			assert.Equal(t, ch.c.PostFundState().TurnNum+transfers, supported.TurnNum)
+			assert.Equal(t, big.NewInt(100-transfers), supported.Outcome[0].Allocations[0].Amount)
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+}
+
+func TestChannelManagerCancel(t *testing.T) {
+	tc := getMockChannel(t, 5, 3)
+	manager := NewChannelManager()
+	require.NoError(t, manager.Add(tc.channel))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	go manager.Do(context.Background(), tc.channel.ID(), func(*Channel) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := manager.Do(ctx, tc.channel.ID(), func(*Channel) error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	err = manager.Do(context.Background(), tc.channel.ID(), func(*Channel) error { return nil })
+	assert.NoError(t, err)
+}
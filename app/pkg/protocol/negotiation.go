@@ -0,0 +1,321 @@
+package protocol
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+	nc "github.com/statechannels/go-nitro/crypto"
+	ntypes "github.com/statechannels/go-nitro/types"
+)
+
+var (
+	ErrNotInvited        = errors.New("negotiation: account is not invited")
+	ErrStaleTerms        = errors.New("negotiation: terms were changed by a counter proposal")
+	ErrNegotiationClosed = errors.New("negotiation: terms are rejected")
+	ErrNotAgreed         = errors.New("negotiation: terms are not accepted by all participants")
+	ErrNotPositive       = errors.New("negotiation: locked amount must be positive")
+	ErrAnswerSignature   = errors.New("negotiation: invalid answer signature")
+)
+
+// Response represents answer of the participant to the current terms.
+type Response int
+
+const (
+	// Invited participant hasn't answered to the current terms yet.
+	Invited Response = iota
+	// Accepted participant agrees on the current terms.
+	Accepted
+	// Rejected participant refuses to open the channel.
+	Rejected
+)
+
+// String returns name of the response.
+func (r Response) String() string {
+	switch r {
+	case Invited:
+		return "Invited"
+	case Accepted:
+		return "Accepted"
+	case Rejected:
+		return "Rejected"
+	default:
+		return "Unknown"
+	}
+}
+
+// Terms represents terms of the channel under negotiation for review by participants.
+// Round is incremented by every counter proposal, responses are given to terms of the round.
+type Terms struct {
+	Round        uint64
+	Participants []Participant
+	Responses    map[common.Address]Response
+}
+
+// Negotiation negotiates terms of the init proposal between participants, the first participant is the initiator.
+// Participants accept or reject the terms or counter them with changes of their own allocation,
+// channel is opened only with terms accepted by all participants. Every participant locks a positive amount.
+// Answers are signed by participants with messages returned by AcceptMessage, RejectMessage and CounterMessage,
+// the participant is recovered from the signature. Negotiation is safe for concurrent use.
+type Negotiation struct {
+	mu        sync.Mutex
+	proposal  *InitProposal
+	round     uint64
+	responses map[common.Address]Response
+}
+
+// NewNegotiation returns negotiation of the proposal, the initiator accepts it and other participants are invited.
+// An error is thrown if the proposal is invalid or any participant doesn't lock a positive amount.
+func NewNegotiation(proposal *InitProposal) (*Negotiation, error) {
+	err := proposal.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range proposal.Participants {
+		if p.LockedAmount.Sign() <= 0 {
+			return nil, ErrNotPositive
+		}
+	}
+
+	n := &Negotiation{proposal: proposal, responses: make(map[common.Address]Response)}
+	for _, p := range proposal.Participants {
+		n.responses[p.Address] = Invited
+	}
+	n.responses[proposal.Participants[0].Address] = Accepted
+
+	return n, nil
+}
+
+// Terms returns copy of the current terms.
+func (n *Negotiation) Terms() Terms {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	terms := Terms{Round: n.round, Responses: make(map[common.Address]Response)}
+	for _, p := range n.proposal.Participants {
+		terms.Participants = append(terms.Participants, Participant{
+			Address:      p.Address,
+			Destination:  p.Destination,
+			LockedAmount: new(big.Int).Set(p.LockedAmount),
+			Index:        p.Index,
+		})
+	}
+
+	for address, response := range n.responses {
+		terms.Responses[address] = response
+	}
+
+	return terms
+}
+
+// AcceptMessage returns message participant signs to accept terms of the round.
+func (n *Negotiation) AcceptMessage(round uint64) ([]byte, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.message("accept", round)
+}
+
+// RejectMessage returns message participant signs to reject terms of the round.
+func (n *Negotiation) RejectMessage(round uint64) ([]byte, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.message("reject", round)
+}
+
+// CounterMessage returns message participant signs to counter terms of the round with the allocation.
+func (n *Negotiation) CounterMessage(round uint64, destination ntypes.Destination, lockedAmount *big.Int) ([]byte, error) {
+	if lockedAmount == nil || lockedAmount.Sign() <= 0 {
+		return nil, ErrNotPositive
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.message("counter", round, destination.Bytes(), common.LeftPadBytes(lockedAmount.Bytes(), 32))
+}
+
+// Accept accepts terms of the round on behalf of the participant who signed AcceptMessage.
+// ErrStaleTerms is returned if terms of the round were countered.
+func (n *Negotiation) Accept(round uint64, signature state.Signature) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	message, err := n.message("accept", round)
+	if err != nil {
+		return err
+	}
+
+	address, err := n.respond(message, round, signature)
+	if err != nil {
+		return err
+	}
+
+	n.responses[address] = Accepted
+
+	return nil
+}
+
+// Reject rejects terms of the round on behalf of the participant who signed RejectMessage, it closes the negotiation.
+func (n *Negotiation) Reject(round uint64, signature state.Signature) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	message, err := n.message("reject", round)
+	if err != nil {
+		return err
+	}
+
+	address, err := n.respond(message, round, signature)
+	if err != nil {
+		return err
+	}
+
+	n.responses[address] = Rejected
+
+	return nil
+}
+
+// Counter proposes new allocation of the participant who signed CounterMessage instead of terms of the round.
+// The participant accepts new terms, other participants are invited to review them.
+func (n *Negotiation) Counter(round uint64, destination ntypes.Destination, lockedAmount *big.Int, signature state.Signature) error {
+	if lockedAmount == nil || lockedAmount.Sign() <= 0 {
+		return ErrNotPositive
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	message, err := n.message("counter", round, destination.Bytes(), common.LeftPadBytes(lockedAmount.Bytes(), 32))
+	if err != nil {
+		return err
+	}
+
+	address, err := n.respond(message, round, signature)
+	if err != nil {
+		return err
+	}
+
+	proposal := &InitProposal{
+		Contract:     n.proposal.Contract,
+		ChannelNonce: n.proposal.ChannelNonce,
+		Codec:        n.proposal.Codec,
+		TurnTaking:   n.proposal.TurnTaking,
+	}
+	prefund := cloneState(*n.proposal.State)
+	proposal.State = &prefund
+
+	for i, p := range n.proposal.Participants {
+		if p.Address == address {
+			p = NewParticipant(p.Address, destination, p.Index, lockedAmount)
+			prefund.Outcome[0].Allocations[i].Destination = destination
+			prefund.Outcome[0].Allocations[i].Amount = lockedAmount
+		}
+		proposal.Participants = append(proposal.Participants, p)
+	}
+
+	err = proposal.Validate()
+	if err != nil {
+		return err
+	}
+
+	n.proposal = proposal
+	n.round++
+	for participant := range n.responses {
+		n.responses[participant] = Invited
+	}
+	n.responses[address] = Accepted
+
+	return nil
+}
+
+// Agreed returns true if all participants accepted the current terms.
+func (n *Negotiation) Agreed() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.agreed()
+}
+
+// Open opens channel with the agreed terms on behalf of participant, prefund state of the channel
+// is the state participants sign. ErrNotAgreed is returned until all participants accept the terms.
+func (n *Negotiation) Open(participantIndex uint) (*Channel, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.rejected() {
+		return nil, ErrNegotiationClosed
+	}
+
+	if !n.agreed() {
+		return nil, ErrNotAgreed
+	}
+
+	return InitChannel(n.proposal, participantIndex)
+}
+
+// message returns message of the answer to terms of the round, it is bound to the channel under negotiation.
+func (n *Negotiation) message(answer string, round uint64, fields ...[]byte) ([]byte, error) {
+	channelID, err := n.proposal.State.ChannelId()
+	if err != nil {
+		return nil, err
+	}
+
+	message := []byte("negotiation:" + answer)
+	message = append(message, channelID.Bytes()...)
+	message = append(message, common.LeftPadBytes(new(big.Int).SetUint64(round).Bytes(), 32)...)
+	for _, field := range fields {
+		message = append(message, field...)
+	}
+
+	return message, nil
+}
+
+// respond returns the participant who signed the message and checks that the participant responds
+// to the current terms of the open negotiation.
+func (n *Negotiation) respond(message []byte, round uint64, signature state.Signature) (common.Address, error) {
+	address, err := nc.RecoverEthereumMessageSigner(message, signature)
+	if err != nil {
+		return common.Address{}, ErrAnswerSignature
+	}
+
+	if _, invited := n.responses[address]; !invited {
+		return common.Address{}, ErrNotInvited
+	}
+
+	if n.rejected() {
+		return common.Address{}, ErrNegotiationClosed
+	}
+
+	if round != n.round {
+		return common.Address{}, ErrStaleTerms
+	}
+
+	return address, nil
+}
+
+// agreed returns true if all participants accepted the current terms.
+func (n *Negotiation) agreed() bool {
+	for _, response := range n.responses {
+		if response != Accepted {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rejected returns true if any participant rejected the terms.
+func (n *Negotiation) rejected() bool {
+	for _, response := range n.responses {
+		if response == Rejected {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,172 @@
+package protocol
+
+import (
+	"app/pkg/nitro"
+	"app/pkg/nitro/mock"
+	"app/pkg/nitro/simulated"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+	nc "github.com/statechannels/go-nitro/crypto"
+	ntypes "github.com/statechannels/go-nitro/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNegotiation returns negotiation of the proposal between test participants.
+func testNegotiation(t *testing.T, lockedAmounts ...int64) (testChannel, *Negotiation) {
+	tc := newTestParticipants(t, lockedAmounts...)
+	tc.adjudicator = mock.NewAdjudicator(simulated.ChainID)
+	client := nitro.Client{Adjudicator: tc.adjudicator, ChainID: simulated.ChainID, Chain: tc.adjudicator, Events: tc.adjudicator}
+
+	proposal := NewInitProposal(tc.participants[0], NewContract(client, common.Address{}))
+	for _, p := range tc.participants[1:] {
+		require.NoError(t, proposal.AddParticipant(p))
+	}
+
+	n, err := NewNegotiation(proposal)
+	require.NoError(t, err)
+
+	return tc, n
+}
+
+// signAnswer returns signature of the answer message made with the key.
+func signAnswer(t *testing.T, key []byte, message []byte, err error) state.Signature {
+	require.NoError(t, err)
+	signature, err := nc.SignEthereumMessage(message, key)
+	require.NoError(t, err)
+
+	return signature
+}
+
+// accept accepts terms of the round with the key.
+func accept(t *testing.T, n *Negotiation, key []byte, round uint64) error {
+	message, err := n.AcceptMessage(round)
+	return n.Accept(round, signAnswer(t, key, message, err))
+}
+
+// counter counters terms of the round with the key.
+func counter(t *testing.T, n *Negotiation, key []byte, round uint64, destination ntypes.Destination, lockedAmount *big.Int) error {
+	message, err := n.CounterMessage(round, destination, lockedAmount)
+	return n.Counter(round, destination, lockedAmount, signAnswer(t, key, message, err))
+}
+
+func TestNegotiation(t *testing.T) {
+	t.Run("invalid proposal", func(t *testing.T) {
+		tc := newTestParticipants(t, 0, 0)
+		proposal := NewInitProposal(tc.participants[0], NewContract(nitro.Client{}, common.Address{}))
+		require.NoError(t, proposal.AddParticipant(tc.participants[1]))
+
+		_, err := NewNegotiation(proposal)
+		assert.ErrorIs(t, err, ErrNoFunds)
+	})
+
+	t.Run("zero locked amount", func(t *testing.T) {
+		tc := newTestParticipants(t, 5, 0)
+		proposal := NewInitProposal(tc.participants[0], NewContract(nitro.Client{ChainID: simulated.ChainID}, common.Address{}))
+		require.NoError(t, proposal.AddParticipant(tc.participants[1]))
+
+		_, err := NewNegotiation(proposal)
+		assert.ErrorIs(t, err, ErrNotPositive)
+	})
+
+	t.Run("invitation", func(t *testing.T) {
+		tc, n := testNegotiation(t, 5, 3)
+
+		terms := n.Terms()
+		assert.Equal(t, uint64(0), terms.Round)
+		assert.Equal(t, Accepted, terms.Responses[tc.participants[0].Address])
+		assert.Equal(t, Invited, terms.Responses[tc.participants[1].Address])
+		require.Len(t, terms.Participants, 2)
+		assert.Equal(t, big.NewInt(3), terms.Participants[1].LockedAmount)
+		assert.False(t, n.Agreed())
+
+		_, err := n.Open(0)
+		assert.ErrorIs(t, err, ErrNotAgreed)
+
+		key, _ := nc.GeneratePrivateKeyAndAddress()
+		assert.ErrorIs(t, accept(t, n, key, 0), ErrNotInvited)
+	})
+
+	t.Run("accept", func(t *testing.T) {
+		tc, n := testNegotiation(t, 5, 3)
+		require.NoError(t, accept(t, n, tc.keys[1], 0))
+		assert.True(t, n.Agreed())
+
+		ch, err := n.Open(1)
+		require.NoError(t, err)
+		for _, key := range tc.keys {
+			_, err := ch.ApproveInitChannel(key)
+			require.NoError(t, err)
+		}
+		assert.True(t, ch.c.PreFundComplete())
+	})
+
+	t.Run("counter", func(t *testing.T) {
+		tc, n := testNegotiation(t, 5, 3)
+		destination := ntypes.Destination(common.HexToHash("0xff"))
+		require.NoError(t, accept(t, n, tc.keys[1], 0))
+
+		require.NoError(t, counter(t, n, tc.keys[1], 0, destination, big.NewInt(4)))
+		terms := n.Terms()
+		assert.Equal(t, uint64(1), terms.Round)
+		assert.Equal(t, Invited, terms.Responses[tc.participants[0].Address])
+		assert.Equal(t, Accepted, terms.Responses[tc.participants[1].Address])
+		assert.Equal(t, big.NewInt(4), terms.Participants[1].LockedAmount)
+		assert.Equal(t, destination, terms.Participants[1].Destination)
+		assert.False(t, n.Agreed())
+
+		assert.ErrorIs(t, accept(t, n, tc.keys[0], 0), ErrStaleTerms)
+		for _, amount := range []*big.Int{big.NewInt(-1), big.NewInt(0), nil} {
+			_, err := n.CounterMessage(1, destination, amount)
+			assert.ErrorIs(t, err, ErrNotPositive)
+			assert.ErrorIs(t, n.Counter(1, destination, amount, state.Signature{}), ErrNotPositive)
+		}
+		assert.Equal(t, uint64(1), n.Terms().Round)
+
+		require.NoError(t, accept(t, n, tc.keys[0], 1))
+		ch, err := n.Open(0)
+		require.NoError(t, err)
+
+		allocations := ch.c.PreFundState().Outcome[0].Allocations
+		assert.Equal(t, big.NewInt(5), allocations[0].Amount)
+		assert.Equal(t, big.NewInt(4), allocations[1].Amount)
+		assert.Equal(t, destination, allocations[1].Destination)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		tc, n := testNegotiation(t, 5, 3)
+		message, err := n.RejectMessage(0)
+		require.NoError(t, n.Reject(0, signAnswer(t, tc.keys[1], message, err)))
+		assert.Equal(t, Rejected, n.Terms().Responses[tc.participants[1].Address])
+
+		assert.ErrorIs(t, accept(t, n, tc.keys[1], 0), ErrNegotiationClosed)
+		assert.ErrorIs(t, counter(t, n, tc.keys[0], 0, tc.participants[0].Destination, big.NewInt(1)), ErrNegotiationClosed)
+
+		_, err = n.Open(0)
+		assert.ErrorIs(t, err, ErrNegotiationClosed)
+	})
+
+	t.Run("answer is signed by the participant", func(t *testing.T) {
+		tc, n := testNegotiation(t, 5, 3)
+
+		// counter signed with another allocation doesn't recover the participant
+		message, err := n.CounterMessage(0, tc.participants[1].Destination, big.NewInt(4))
+		signature := signAnswer(t, tc.keys[1], message, err)
+		assert.ErrorIs(t, n.Counter(0, tc.participants[1].Destination, big.NewInt(10), signature), ErrNotInvited)
+
+		// acceptance of the initiator can't be replayed as an answer of the other participant
+		message, err = n.AcceptMessage(0)
+		require.NoError(t, n.Accept(0, signAnswer(t, tc.keys[0], message, err)))
+		assert.Equal(t, Invited, n.Terms().Responses[tc.participants[1].Address])
+
+		assert.ErrorIs(t, n.Accept(0, state.Signature{R: make([]byte, 32), S: make([]byte, 32)}), ErrAnswerSignature)
+
+		terms := n.Terms()
+		assert.Equal(t, uint64(0), terms.Round)
+		assert.Equal(t, big.NewInt(3), terms.Participants[1].LockedAmount)
+		assert.False(t, n.Agreed())
+	})
+}
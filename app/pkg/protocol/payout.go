@@ -0,0 +1,215 @@
+package protocol
+
+import (
+	"app/internal/asset"
+	"app/pkg/eth/gasprice"
+	"app/pkg/nitro"
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+)
+
+var (
+	ErrInvalidPayoutIndices = errors.New("channel: payout indices must be strictly increasing allocation indices")
+	ErrGuaranteePayout      = errors.New("channel: guarantee allocation can't be paid out")
+)
+
+// registeredOutcome is outcome registered on-chain after partial payouts of the channel.
+type registeredOutcome struct {
+	stateHash [32]byte
+	exit      outcome.Exit
+}
+
+// Finalize finalizes channel on-chain with the final state signed by all participants without paying out funds.
+// Funds are paid out afterwards with Payout.
+// It returns on-chain transaction with detailed information.
+func (channel *Channel) Finalize(ctx context.Context, p *Participant, privateKey []byte, participantSignatures map[common.Address]state.Signature, opts ...gasprice.Station) (*types.Transaction, error) {
+	lastState := channel.currentState()
+	if !lastState.IsFinal {
+		return &types.Transaction{}, ErrNotFinalState
+	}
+
+	concludeParams, err := buildConcludeParams(&lastState, participantSignatures)
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+
+	adjudicator := channel.initProposal.Contract.Client.Adjudicator
+	transaction, err := adjudicator.Conclude(transactOpts(ctx, channel.c.ChainId, p, privateKey, opts...),
+		big.NewInt(int64(lastState.TurnNum)),
+		concludeParams.FixedPart,
+		concludeParams.AppData,
+		concludeParams.OutcomeState,
+		concludeParams.NumStates,
+		concludeParams.WhoSignedWhat,
+		concludeParams.Signatures,
+	)
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+
+	return transaction, nil
+}
+
+// Payout transfers funds of the channel finalized on-chain to destinations of allocations at indices,
+// all allocations are paid out when indices are empty. Indices must be strictly increasing.
+// Outcome left after the payout is used by the next payouts once the transaction is confirmed with ConfirmTransaction.
+// It returns on-chain transaction with detailed information.
+func (channel *Channel) Payout(ctx context.Context, p *Participant, privateKey []byte, indices []uint, opts ...gasprice.Station) (*types.Transaction, error) {
+	status, err := channel.OnChainStatus(ctx)
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+
+	if status.Mode != nitro.Finalized {
+		return &types.Transaction{}, ErrNotFinalized
+	}
+
+	registered, err := channel.registeredOutcome(status)
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+
+	assetIndex := -1
+	for i, assetExit := range registered.exit {
+		if assetExit.Asset == channel.initProposal.Contract.AssetAddress {
+			assetIndex = i
+			break
+		}
+	}
+	if assetIndex < 0 {
+		return &types.Transaction{}, asset.ErrAssetNotInChannel
+	}
+
+	holdings, err := channel.CheckHoldings(ctx)
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+
+	allocations, err := transferAllocations(holdings, registered.exit[assetIndex].Allocations, indices)
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+
+	outcomeBytes, err := registered.exit.Encode()
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+
+	bigIndices := make([]*big.Int, len(indices))
+	for i, index := range indices {
+		bigIndices[i] = new(big.Int).SetUint64(uint64(index))
+	}
+
+	adjudicator := channel.initProposal.Contract.Client.Adjudicator
+	transaction, err := adjudicator.Transfer(transactOpts(ctx, channel.c.ChainId, p, privateKey, opts...),
+		big.NewInt(int64(assetIndex)),
+		channel.c.Id,
+		outcomeBytes,
+		registered.stateHash,
+		bigIndices,
+	)
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+
+	exit := registered.exit.Clone()
+	exit[assetIndex].Allocations = allocations
+	channel.mu.Lock()
+	if channel.pendingPayouts == nil {
+		channel.pendingPayouts = make(map[common.Hash]*registeredOutcome)
+	}
+	channel.pendingPayouts[transaction.Hash()] = &registeredOutcome{stateHash: registered.stateHash, exit: exit}
+	channel.mu.Unlock()
+	if paidOut(exit) {
+		channel.closing(transaction)
+	}
+
+	return transaction, nil
+}
+
+// registeredOutcome returns outcome matching on-chain fingerprint, the outcome left after
+// the latest confirmed payout is checked before the signed states.
+func (channel *Channel) registeredOutcome(status OnChainStatus) (*registeredOutcome, error) {
+	channel.mu.RLock()
+	defer channel.mu.RUnlock()
+
+	if channel.registered != nil {
+		outcomeBytes, err := channel.registered.exit.Encode()
+		if err != nil {
+			return nil, err
+		}
+
+		fingerprint := nitro.Fingerprint(channel.registered.stateHash, crypto.Keccak256Hash(outcomeBytes))
+		if fingerprint.Cmp(status.Fingerprint) == 0 {
+			return channel.registered, nil
+		}
+	}
+
+	stateHash, outcomeBytes, found, err := registeredState(status.Fingerprint, channel.signedStates(status.TurnNumRecord)...)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrUnknownFingerprint
+	}
+
+	exit, err := outcome.Decode(outcomeBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &registeredOutcome{stateHash: stateHash, exit: exit}, nil
+}
+
+// transferAllocations returns allocations left after payout of allocations at indices,
+// it mirrors computation of the adjudicator: allocations are paid out in order while holdings suffice.
+func transferAllocations(holdings *big.Int, allocations outcome.Allocations, indices []uint) (outcome.Allocations, error) {
+	for i, index := range indices {
+		if index >= uint(len(allocations)) || (i > 0 && indices[i-1] >= index) {
+			return nil, ErrInvalidPayoutIndices
+		}
+	}
+
+	newAllocations := allocations.Clone()
+	surplus := new(big.Int).Set(holdings)
+	k := 0
+	for i, allocation := range allocations {
+		affords := new(big.Int).Set(allocation.Amount)
+		if surplus.Cmp(affords) < 0 {
+			affords.Set(surplus)
+		}
+
+		if len(indices) == 0 || (k < len(indices) && indices[k] == uint(i)) {
+			if allocation.AllocationType == outcome.GuaranteeAllocationType {
+				return nil, ErrGuaranteePayout
+			}
+
+			newAllocations[i].Amount = new(big.Int).Sub(allocation.Amount, affords)
+			k++
+		}
+
+		surplus.Sub(surplus, affords)
+	}
+
+	return newAllocations, nil
+}
+
+// paidOut returns true if all allocations of the exit are paid out.
+func paidOut(exit outcome.Exit) bool {
+	for _, assetExit := range exit {
+		for _, allocation := range assetExit.Allocations {
+			if allocation.Amount.Sign() != 0 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,186 @@
+package protocol
+
+import (
+	"app/pkg/nitro"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	ntypes "github.com/statechannels/go-nitro/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revertingAdjudicator is an adjudicator which submits transfers that are reverted on-chain.
+type revertingAdjudicator struct {
+	nitro.StateChannelContract
+}
+
+func (revertingAdjudicator) Transfer(opts *bind.TransactOpts, assetIndex *big.Int, fromChannelId [32]byte, outcomeBytes []byte, stateHash [32]byte, indices []*big.Int) (*types.Transaction, error) {
+	return types.NewTx(&types.LegacyTx{Nonce: 1}), nil
+}
+
+func TestFinalize(t *testing.T) {
+	t.Run("non final state can't be finalized", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 1, false)
+
+		_, err := tc.channel.Finalize(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		assert.ErrorIs(t, err, ErrNotFinalState)
+	})
+
+	t.Run("funds are kept until payout", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 2, true)
+
+		_, err := tc.channel.Payout(context.Background(), tc.participants[0], tc.keys[0], nil)
+		assert.ErrorIs(t, err, ErrNotFinalized)
+
+		_, err = tc.channel.Finalize(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		require.NoError(t, err)
+
+		status, err := tc.channel.OnChainStatus(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, nitro.Finalized, status.Mode)
+		assert.True(t, status.FingerprintMatch)
+
+		holdings, err := tc.channel.CheckHoldings(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(8), holdings)
+	})
+}
+
+func TestPayout(t *testing.T) {
+	t.Run("pay out destinations one by one", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 2, true)
+		_, err := tc.channel.Finalize(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		require.NoError(t, err)
+
+		tx, err := tc.channel.Payout(context.Background(), tc.participants[1], tc.keys[1], []uint{1})
+		require.NoError(t, err)
+		assert.Zero(t, tc.adjudicator.Payout(common.Address{}, tc.participants[0].Address).Sign())
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+		assert.Equal(t, Concluding, tc.channel.Lifecycle())
+		receipt, err := mined(tx)
+		require.NoError(t, err)
+		tc.channel.ConfirmTransaction(receipt)
+
+		tx, err = tc.channel.Payout(context.Background(), tc.participants[0], tc.keys[0], []uint{0})
+		require.NoError(t, err)
+		assert.Equal(t, Closing, tc.channel.Lifecycle())
+		receipt, err = mined(tx)
+		require.NoError(t, err)
+		tc.channel.ConfirmTransaction(receipt)
+		assert.Equal(t, Closed, tc.channel.Lifecycle())
+		assert.Equal(t, big.NewInt(3), tc.adjudicator.Payout(common.Address{}, tc.participants[0].Address))
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+
+		holdings, err := tc.channel.CheckHoldings(context.Background())
+		assert.NoError(t, err)
+		assert.Zero(t, holdings.Sign())
+	})
+
+	t.Run("pay out all destinations", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 2, true)
+		_, err := tc.channel.Finalize(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		require.NoError(t, err)
+
+		_, err = tc.channel.Payout(context.Background(), tc.participants[0], tc.keys[0], nil)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(3), tc.adjudicator.Payout(common.Address{}, tc.participants[0].Address))
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+	})
+
+	t.Run("pay out after challenge", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 2, false)
+		tc.challenge(t, 1, signatures)
+		tc.adjudicator.IncreaseTime(time.Duration(tc.channel.lastState.ChallengeDuration.Int64()) * time.Second)
+
+		_, err := tc.channel.Payout(context.Background(), tc.participants[1], tc.keys[1], []uint{1})
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+
+		holdings, err := tc.channel.CheckHoldings(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(3), holdings)
+	})
+
+	t.Run("reverted payout", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 2, true)
+		_, err := tc.channel.Finalize(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		require.NoError(t, err)
+
+		tx, err := tc.channel.Payout(context.Background(), tc.participants[1], tc.keys[1], []uint{1})
+		require.NoError(t, err)
+		receipt, err := mined(tx)
+		require.NoError(t, err)
+		tc.channel.ConfirmTransaction(receipt)
+
+		client := &tc.channel.initProposal.Contract.Client
+		client.Adjudicator = revertingAdjudicator{StateChannelContract: tc.adjudicator}
+		tx, err = tc.channel.Payout(context.Background(), tc.participants[0], tc.keys[0], []uint{0})
+		require.NoError(t, err)
+		assert.Equal(t, Closing, tc.channel.Lifecycle())
+		tc.channel.ConfirmTransaction(&types.Receipt{Status: types.ReceiptStatusFailed, TxHash: tx.Hash()})
+		assert.Equal(t, Concluding, tc.channel.Lifecycle())
+
+		client.Adjudicator = tc.adjudicator
+		_, err = tc.channel.Payout(context.Background(), tc.participants[0], tc.keys[0], []uint{0})
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(3), tc.adjudicator.Payout(common.Address{}, tc.participants[0].Address))
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+	})
+
+	t.Run("invalid indices", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 2, true)
+		_, err := tc.channel.Finalize(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		require.NoError(t, err)
+
+		for _, indices := range [][]uint{{2}, {1, 0}, {1, 1}} {
+			_, err = tc.channel.Payout(context.Background(), tc.participants[0], tc.keys[0], indices)
+			assert.ErrorIs(t, err, ErrInvalidPayoutIndices)
+		}
+	})
+}
+
+func TestTransferAllocations(t *testing.T) {
+	allocations := outcome.Allocations{
+		{Destination: ntypes.Destination(common.HexToHash("0x01")), Amount: big.NewInt(3)},
+		{Destination: ntypes.Destination(common.HexToHash("0x02")), Amount: big.NewInt(5)},
+		{Destination: ntypes.Destination(common.HexToHash("0x03")), Amount: big.NewInt(2)},
+	}
+
+	cases := []struct {
+		name     string
+		holdings int64
+		indices  []uint
+		expected []int64
+	}{
+		{"all allocations", 10, nil, []int64{0, 0, 0}},
+		{"single allocation", 10, []uint{1}, []int64{3, 0, 2}},
+		{"underfunded allocations are paid out in order", 6, []uint{1, 2}, []int64{3, 2, 2}},
+		{"no holdings", 0, []uint{0}, []int64{3, 5, 2}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := transferAllocations(big.NewInt(c.holdings), allocations, c.indices)
+			require.NoError(t, err)
+			for i, amount := range c.expected {
+				assert.Zero(t, big.NewInt(amount).Cmp(result[i].Amount))
+			}
+		})
+	}
+
+	assert.Equal(t, big.NewInt(5), allocations[1].Amount)
+}
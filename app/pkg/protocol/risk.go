@@ -0,0 +1,94 @@
+package protocol
+
+import (
+	"app/internal/asset"
+	"app/internal/liability"
+	"app/internal/risk"
+	"errors"
+
+	"github.com/shopspring/decimal"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+)
+
+var (
+	ErrNoAssetRegistry = errors.New("channel: asset registry is required")
+)
+
+// Assess returns exposure of every participant compared with collateral held in the proposed outcome.
+// Assessments flag participants who exceed collateral ratio or need to top up.
+func (sp *StateProposal) Assess() ([]risk.Assessment, error) {
+	if sp.risk == nil {
+		return nil, nil
+	}
+
+	collateral, err := collateral(sp.Outcome(), sp.liabilitiesState, sp.assets)
+	if err != nil {
+		return nil, err
+	}
+
+	return sp.risk.Assess(sp.liabilitiesState, collateral)
+}
+
+// checkLiabilities returns an error if liabilities refer to assets which aren't in the outcome
+// or exceed collateral ratio of the risk policy. Checks are skipped if registry or policy isn't set.
+func checkLiabilities(ls liability.LiabilitiesState, exit outcome.Exit, assets *asset.Registry, policy *risk.Policy) error {
+	if assets != nil {
+		err := assets.ValidateLiabilities(ls, exit)
+		if err != nil {
+			return err
+		}
+	}
+
+	if policy == nil {
+		return nil
+	}
+
+	collateral, err := collateral(exit, ls, assets)
+	if err != nil {
+		return err
+	}
+
+	return policy.Check(ls, collateral)
+}
+
+// collateral returns outcome allocations of every liability asset converted to liability amounts.
+// Allocations are expected to be ordered by participant index.
+func collateral(exit outcome.Exit, ls liability.LiabilitiesState, assets *asset.Registry) (risk.Collateral, error) {
+	if assets == nil {
+		return nil, ErrNoAssetRegistry
+	}
+
+	symbols := make(map[liability.Asset]struct{})
+	for _, kind := range []liability.Kind{liability.Pending, liability.Executed} {
+		for _, o := range ls.Obligations(kind) {
+			symbols[o.Asset] = struct{}{}
+		}
+	}
+
+	collateral := make(risk.Collateral)
+	for symbol := range symbols {
+		info, err := assets.Lookup(symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		allocations, err := assetAllocations(exit, info.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		for index, allocation := range allocations {
+			amount, err := assets.FromUnits(symbol, allocation.Amount)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, found := collateral[uint(index)]; !found {
+				collateral[uint(index)] = make(map[liability.Asset]decimal.Decimal)
+			}
+			collateral[uint(index)][symbol] = amount
+		}
+	}
+
+	return collateral, nil
+}
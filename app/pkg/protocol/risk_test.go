@@ -0,0 +1,124 @@
+package protocol
+
+import (
+	"app/internal/asset"
+	"app/internal/liability"
+	"app/internal/risk"
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiskPolicy(t *testing.T) {
+	assets, err := asset.NewRegistry(asset.Info{Symbol: "ETH", Address: common.HexToAddress("0x"), Decimals: 2})
+	assert.NoError(t, err)
+
+	policy, err := risk.NewPolicy(decimal.NewFromInt(1), decimal.NewFromFloat(0.8), nil)
+	assert.NoError(t, err)
+
+	t.Run("policy without asset registry", func(t *testing.T) {
+		sp := getSettlementProposal(t)
+		sp.risk = policy
+		sp.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(1))
+
+		err := sp.ApproveLiabilities()
+		assert.ErrorIs(t, err, ErrNoAssetRegistry)
+	})
+
+	t.Run("exposure exceeds collateral", func(t *testing.T) {
+		sp := getSettlementProposal(t)
+		sp.assets, sp.risk = assets, policy
+		sp.PendingLiability(1, 0, "ETH", decimal.NewFromFloat(5.01))
+
+		err := sp.ApproveLiabilities()
+		assert.ErrorIs(t, err, risk.ErrExposureLimit)
+		assert.Empty(t, sp.AppData())
+	})
+
+	t.Run("participant is flagged to top up", func(t *testing.T) {
+		sp := getSettlementProposal(t)
+		sp.assets, sp.risk = assets, policy
+		sp.PendingLiability(1, 0, "ETH", decimal.NewFromFloat(4.5))
+
+		err := sp.ApproveLiabilities()
+		assert.NoError(t, err)
+
+		assessments, err := sp.Assess()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(assessments))
+		assert.Equal(t, uint(1), assessments[0].Participant)
+		assert.True(t, assessments[0].Collateral.Equal(decimal.NewFromInt(5)))
+		assert.False(t, assessments[0].Breach)
+		assert.True(t, assessments[0].TopUp)
+	})
+}
+
+func TestSignStateRiskPolicy(t *testing.T) {
+	assets, err := asset.NewRegistry(asset.Info{Symbol: "ETH", Address: common.Address{}, Decimals: 0})
+	require.NoError(t, err)
+
+	policy, err := risk.NewPolicy(decimal.NewFromInt(1), decimal.NewFromFloat(0.8), nil)
+	require.NoError(t, err)
+
+	getRiskChannel := func(t *testing.T) testChannel {
+		tc := getMockChannel(t, 5, 3)
+		tc.channel.initProposal.Contract.Assets = assets
+		tc.channel.initProposal.Contract.Risk = policy
+
+		return tc
+	}
+
+	tests := []struct {
+		name   string
+		asset  liability.Asset
+		amount int64
+		err    error
+	}{
+		{"liability within collateral", "ETH", 2, nil},
+		{"exposure exceeds collateral", "ETH", 4, risk.ErrExposureLimit},
+		{"unknown asset", "BTC", 1, asset.ErrUnknownAsset},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tc := getRiskChannel(t)
+			sp, err := tc.channel.ProposeState()
+			require.NoError(t, err)
+
+			// liabilities are set without approval, signing must check them anyway
+			require.NoError(t, sp.PendingLiability(1, 0, test.asset, decimal.NewFromInt(test.amount)))
+			require.NoError(t, sp.SetApp(sp.liabilitiesState))
+
+			_, err = tc.channel.SignState(context.Background(), sp, tc.keys[0])
+			_, found := tc.channel.c.SignedStateForTurnNum[sp.TurnNum()]
+			if test.err == nil {
+				assert.NoError(t, err)
+				assert.True(t, found)
+			} else {
+				assert.ErrorIs(t, err, test.err)
+				assert.False(t, found)
+			}
+		})
+	}
+
+	t.Run("counterparty state exceeding collateral is rejected", func(t *testing.T) {
+		tc := getRiskChannel(t)
+
+		ls := make(liability.LiabilitiesState)
+		ls.AddPendingLiability(1, 0, "ETH", decimal.NewFromInt(4))
+		s := tc.channel.CurrentState().Clone()
+		s.TurnNum++
+		appData, err := ls.EncodeToBytes()
+		require.NoError(t, err)
+		s.AppData = appData
+
+		err = tc.channel.AddSignature(&s, signatureOf(t, s, tc.keys[1]))
+		assert.ErrorIs(t, err, risk.ErrExposureLimit)
+		_, found := tc.channel.c.SignedStateForTurnNum[s.TurnNum]
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,95 @@
+package protocol
+
+import (
+	"app/internal/asset"
+	"app/internal/liability"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+)
+
+var (
+	ErrUnknownParticipant = errors.New("channel: liability participant has no outcome allocation")
+	ErrNegativeAllocation = errors.New("channel: settlement results in negative allocation")
+)
+
+// Settle nets executed liabilities into outcome allocations and sets proposed state to final.
+// Liability amounts are converted to outcome units with the asset registry.
+// Pending liabilities are not settled and final state isn't settled again. An error is thrown
+// if any allocation would go negative, in that case proposed state stays unchanged.
+func (sp *StateProposal) Settle(assets *asset.Registry) error {
+	if sp.IsFinal() {
+		return nil
+	}
+
+	exit, err := settle(sp.Outcome(), sp.liabilitiesState, assets)
+	if err != nil {
+		return err
+	}
+
+	sp.state.Outcome = exit
+	sp.state.IsFinal = true
+
+	return nil
+}
+
+// settle returns a copy of outcome with executed liabilities moved from debtor to creditor allocations.
+// Allocations are expected to be ordered by participant index.
+func settle(exit outcome.Exit, ls liability.LiabilitiesState, assets *asset.Registry) (outcome.Exit, error) {
+	settled := exit.Clone()
+
+	for from, liabilitiesMap := range ls {
+		for to, liabilities := range liabilitiesMap {
+			for symbol, amount := range liabilities.ExecutedAmounts() {
+				if assets == nil {
+					return nil, ErrNoAssetRegistry
+				}
+
+				info, err := assets.Lookup(symbol)
+				if err != nil {
+					return nil, err
+				}
+
+				allocations, err := assetAllocations(settled, info.Address)
+				if err != nil {
+					return nil, err
+				}
+
+				if int(from) >= len(allocations) || int(to) >= len(allocations) {
+					return nil, ErrUnknownParticipant
+				}
+
+				units, err := assets.ToUnits(symbol, amount)
+				if err != nil {
+					return nil, err
+				}
+
+				allocations[from].Amount = new(big.Int).Sub(allocations[from].Amount, units)
+				allocations[to].Amount = new(big.Int).Add(allocations[to].Amount, units)
+			}
+		}
+	}
+
+	for _, singleAssetExit := range settled {
+		for _, allocation := range singleAssetExit.Allocations {
+			if allocation.Amount.Sign() < 0 {
+				return nil, ErrNegativeAllocation
+			}
+		}
+	}
+
+	return settled, nil
+}
+
+// assetAllocations returns allocations of the outcome asset.
+func assetAllocations(exit outcome.Exit, assetAddress common.Address) (outcome.Allocations, error) {
+	for _, singleAssetExit := range exit {
+		if singleAssetExit.Asset == assetAddress {
+			return singleAssetExit.Allocations, nil
+		}
+	}
+
+	return nil, asset.ErrAssetNotInChannel
+}
@@ -0,0 +1,124 @@
+package protocol
+
+import (
+	"app/internal/asset"
+	"app/pkg/nitro"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/statechannels/go-nitro/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getSettlementProposal(t *testing.T) *StateProposal {
+	participant1 := NewParticipant(common.HexToAddress("0x01"), types.Destination(common.HexToHash("0x01")), uint(0), big.NewInt(1000))
+	participant2 := NewParticipant(common.HexToAddress("0x02"), types.Destination(common.HexToHash("0x02")), uint(1), big.NewInt(500))
+	contract := NewContract(nitro.Client{ChainID: big.NewInt(2)}, common.HexToAddress("0x"))
+	proposal := NewInitProposal(participant1, contract)
+	require.NoError(t, proposal.AddParticipant(participant2))
+
+	state := proposal.State.Clone()
+	sp, err := NewStateProposal(&state)
+	assert.NoError(t, err)
+
+	return sp
+}
+
+func TestSettle(t *testing.T) {
+	assets, err := asset.NewRegistry(
+		asset.Info{Symbol: "ETH", Address: common.HexToAddress("0x"), Decimals: 2},
+		asset.Info{Symbol: "BTC", Address: common.HexToAddress("0x01"), Decimals: 8},
+	)
+	assert.NoError(t, err)
+
+	t.Run("successful settlement", func(t *testing.T) {
+		sp := getSettlementProposal(t)
+		sp.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(3))
+		sp.PendingLiability(1, 0, "ETH", decimal.NewFromFloat(1))
+		assert.NoError(t, sp.ExecutedLiability(0, 1, "ETH", decimal.NewFromFloat(2.5)))
+		assert.NoError(t, sp.ExecutedLiability(1, 0, "ETH", decimal.NewFromFloat(1)))
+
+		err := sp.Settle(assets)
+		assert.NoError(t, err)
+		assert.True(t, sp.IsFinal())
+
+		allocations := sp.state.Outcome[0].Allocations
+		assert.Equal(t, big.NewInt(850), allocations[0].Amount)
+		assert.Equal(t, big.NewInt(650), allocations[1].Amount)
+	})
+
+	t.Run("negative allocation", func(t *testing.T) {
+		sp := getSettlementProposal(t)
+		sp.PendingLiability(1, 0, "ETH", decimal.NewFromFloat(6))
+		assert.NoError(t, sp.ExecutedLiability(1, 0, "ETH", decimal.NewFromFloat(6)))
+
+		err := sp.Settle(assets)
+		assert.ErrorIs(t, err, ErrNegativeAllocation)
+		assert.False(t, sp.IsFinal())
+		assert.Equal(t, big.NewInt(500), sp.state.Outcome[0].Allocations[1].Amount)
+	})
+
+	t.Run("unknown asset", func(t *testing.T) {
+		sp := getSettlementProposal(t)
+		sp.PendingLiability(0, 1, "LTC", decimal.NewFromFloat(1))
+		assert.NoError(t, sp.ExecutedLiability(0, 1, "LTC", decimal.NewFromFloat(1)))
+
+		err := sp.Settle(assets)
+		assert.ErrorIs(t, err, asset.ErrUnknownAsset)
+	})
+
+	t.Run("asset is not in channel", func(t *testing.T) {
+		sp := getSettlementProposal(t)
+		sp.PendingLiability(0, 1, "BTC", decimal.NewFromFloat(1))
+		assert.NoError(t, sp.ExecutedLiability(0, 1, "BTC", decimal.NewFromFloat(1)))
+
+		err := sp.Settle(assets)
+		assert.ErrorIs(t, err, asset.ErrAssetNotInChannel)
+	})
+
+	t.Run("amount exceeds precision", func(t *testing.T) {
+		sp := getSettlementProposal(t)
+		sp.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(0.001))
+		assert.NoError(t, sp.ExecutedLiability(0, 1, "ETH", decimal.NewFromFloat(0.001)))
+
+		err := sp.Settle(assets)
+		assert.ErrorIs(t, err, asset.ErrPrecisionLoss)
+	})
+
+	t.Run("missing asset registry", func(t *testing.T) {
+		sp := getSettlementProposal(t)
+		sp.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(1))
+		assert.NoError(t, sp.ExecutedLiability(0, 1, "ETH", decimal.NewFromFloat(1)))
+
+		err := sp.Settle(nil)
+		assert.ErrorIs(t, err, ErrNoAssetRegistry)
+		assert.False(t, sp.IsFinal())
+	})
+
+	t.Run("final state settles with channel registry", func(t *testing.T) {
+		sp := getSettlementProposal(t)
+		sp.assets = assets
+		sp.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(2))
+		assert.NoError(t, sp.ExecutedLiability(0, 1, "ETH", decimal.NewFromFloat(2)))
+
+		assert.NoError(t, sp.SetFinal())
+		assert.True(t, sp.IsFinal())
+		assert.Equal(t, big.NewInt(800), sp.state.Outcome[0].Allocations[0].Amount)
+
+		// final state isn't settled again
+		assert.NoError(t, sp.Settle(assets))
+		assert.Equal(t, big.NewInt(800), sp.state.Outcome[0].Allocations[0].Amount)
+	})
+
+	t.Run("pending liabilities are not settled", func(t *testing.T) {
+		sp := getSettlementProposal(t)
+		sp.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(1))
+
+		err := sp.Settle(assets)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(1000), sp.state.Outcome[0].Allocations[0].Amount)
+	})
+}
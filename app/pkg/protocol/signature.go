@@ -0,0 +1,32 @@
+package protocol
+
+import (
+	"errors"
+
+	"github.com/statechannels/go-nitro/channel/state"
+)
+
+// SignatureLength is the length of signature encoded in [R || S || V] format.
+const SignatureLength = 65
+
+var (
+	ErrSignatureLength = errors.New("channel: signature must be 65 bytes")
+)
+
+// EncodeSignature returns signature in [R || S || V] format.
+func EncodeSignature(signature state.Signature) []byte {
+	encoded := make([]byte, 0, SignatureLength)
+	encoded = append(encoded, signature.R...)
+	encoded = append(encoded, signature.S...)
+	return append(encoded, signature.V)
+}
+
+// DecodeSignature returns signature from its [R || S || V] encoding.
+// An error is thrown if encoded signature isn't 65 bytes long.
+func DecodeSignature(encoded []byte) (state.Signature, error) {
+	if len(encoded) != SignatureLength {
+		return state.Signature{}, ErrSignatureLength
+	}
+
+	return state.Signature{R: encoded[:32], S: encoded[32:64], V: encoded[64]}, nil
+}
@@ -0,0 +1,30 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureEncoding(t *testing.T) {
+	t.Run("encoded signature is decoded", func(t *testing.T) {
+		signature := state.Signature{R: common.HexToHash("0x01").Bytes(), S: common.HexToHash("0x02").Bytes(), V: 27}
+
+		encoded := EncodeSignature(signature)
+		assert.Len(t, encoded, SignatureLength)
+
+		decoded, err := DecodeSignature(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, signature, decoded)
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		for _, length := range []int{0, 64, 66} {
+			_, err := DecodeSignature(make([]byte, length))
+			assert.ErrorIs(t, err, ErrSignatureLength)
+		}
+	})
+}
@@ -1,33 +1,47 @@
 package protocol
 
 import (
+	"app/internal/asset"
 	"app/internal/liability"
-	"errors"
+	"app/internal/risk"
+	"time"
 
 	"github.com/shopspring/decimal"
 	st "github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
 	"github.com/statechannels/go-nitro/types"
 )
 
 // StateProposal represents information about proposed state.
+// Liabilities are available only if channel app is liabilities app.
 type StateProposal struct {
 	state            *st.State
+	codec            AppCodec
+	app              interface{}
 	liabilitiesState liability.LiabilitiesState
+	assets           *asset.Registry
+	risk             *risk.Policy
 }
 
-// NewStateProposal creates state proposal from state.
+// NewStateProposal creates state proposal from state of liabilities app.
 func NewStateProposal(state *st.State) (*StateProposal, error) {
-	liabilitiesState, err := liability.DecodeFromBytes(state.AppData)
-	if errors.Is(err, liability.ErrEmptyByteArray) {
-		liabilitiesState = make(liability.LiabilitiesState)
-	} else if err != nil {
+	return NewStateProposalWithCodec(state, LiabilitiesCodec{})
+}
+
+// NewStateProposalWithCodec creates state proposal from state, app data is decoded with the codec.
+func NewStateProposalWithCodec(state *st.State, codec AppCodec) (*StateProposal, error) {
+	app, err := codec.Decode(state.AppData)
+	if err != nil {
 		return &StateProposal{}, err
 	}
 
-	return &StateProposal{
-		state:            state,
-		liabilitiesState: liabilitiesState,
-	}, nil
+	sp := &StateProposal{
+		state: state,
+		codec: codec,
+	}
+	sp.setApp(app)
+
+	return sp, nil
 }
 
 // TurnNum returns proposed state turn num.
@@ -35,9 +49,15 @@ func (sp *StateProposal) TurnNum() uint64 {
 	return sp.state.TurnNum
 }
 
-// SetFinal sets proposed state to final.
-func (sp *StateProposal) SetFinal() {
-	sp.state.IsFinal = true
+// State returns copy of the proposed state.
+func (sp *StateProposal) State() st.State {
+	return cloneState(*sp.state)
+}
+
+// SetFinal settles executed liabilities into outcome with the channel asset registry
+// and sets proposed state to final.
+func (sp *StateProposal) SetFinal() error {
+	return sp.Settle(sp.assets)
 }
 
 // IsFinal returns either proposed state is final or not.
@@ -55,19 +75,121 @@ func (sp *StateProposal) SetAppData(appData []byte) {
 	sp.state.AppData = appData
 }
 
+// Outcome returns a copy of proposed state outcome.
+func (sp *StateProposal) Outcome() outcome.Exit {
+	return sp.state.Outcome.Clone()
+}
+
+// SetOutcome sets proposed state outcome.
+func (sp *StateProposal) SetOutcome(exit outcome.Exit) {
+	sp.state.Outcome = exit
+}
+
+// App returns proposed state app decoded with channel app codec.
+func (sp *StateProposal) App() interface{} {
+	return sp.app
+}
+
+// SetApp sets proposed state app and encodes it into app data.
+func (sp *StateProposal) SetApp(app interface{}) error {
+	appData, err := sp.codec.Encode(app)
+	if err != nil {
+		return err
+	}
+
+	sp.SetAppData(appData)
+	sp.setApp(app)
+
+	return nil
+}
+
+// setApp sets decoded app, liabilities state is empty if app is not liabilities app.
+func (sp *StateProposal) setApp(app interface{}) {
+	sp.app = app
+
+	liabilitiesState, ok := app.(liability.LiabilitiesState)
+	if !ok {
+		liabilitiesState = make(liability.LiabilitiesState)
+	}
+	sp.liabilitiesState = liabilitiesState
+}
+
+// isLiabilitiesApp returns true if proposed state app is liabilities app.
+func (sp *StateProposal) isLiabilitiesApp() bool {
+	_, ok := sp.app.(liability.LiabilitiesState)
+	return ok
+}
+
 // LiabilityState returns proposed state liability.
 func (sp *StateProposal) LiabilityState() liability.LiabilitiesState {
 	return sp.liabilitiesState
 }
 
-// PendingLiability add pending liability to state proposal.
-func (sp *StateProposal) PendingLiability(from, to uint, asset liability.Asset, amount decimal.Decimal) {
-	sp.liabilitiesState.AddPendingLiability(from, to, asset, amount)
+// PendingLiability add pending liability to state proposal. An error is thrown if amount is not positive.
+func (sp *StateProposal) PendingLiability(from, to uint, asset liability.Asset, amount decimal.Decimal) error {
+	return sp.liabilitiesState.AddPendingEntry(from, to, asset, sp.pendingEntry(amount, "", 0))
+}
+
+// PendingLiabilityWithReference adds pending liability created by the trade or order with the given reference.
+// Zero expiry means liability doesn't expire, otherwise it is reverted once unix time reaches expiry.
+func (sp *StateProposal) PendingLiabilityWithReference(from, to uint, asset liability.Asset, amount decimal.Decimal, reference string, expiry int64) error {
+	return sp.liabilitiesState.AddPendingEntry(from, to, asset, sp.pendingEntry(amount, reference, expiry))
+}
+
+// ExecutedLiabilityByReference executes pending liability with the given reference.
+// Zero amount executes the whole remaining amount of the liability.
+func (sp *StateProposal) ExecutedLiabilityByReference(reference string, amount decimal.Decimal) error {
+	execution := liability.Execution{
+		Amount:    amount,
+		TurnNum:   sp.TurnNum(),
+		Timestamp: time.Now().Unix(),
+	}
+
+	return sp.liabilitiesState.ExecuteByReference(reference, execution)
+}
+
+// RevertLiabilityByReference reverts pending liability with the given reference.
+func (sp *StateProposal) RevertLiabilityByReference(reference string) error {
+	return sp.liabilitiesState.RevertByReference(reference)
+}
+
+// RevertExpiredLiabilities reverts pending liabilities expired by the given unix time
+// and returns number of reverted liabilities. App data is updated if any liability was reverted.
+func (sp *StateProposal) RevertExpiredLiabilities(now int64) (int, error) {
+	reverted := sp.liabilitiesState.RevertExpired(now)
+	if reverted == 0 {
+		return 0, nil
+	}
+
+	err := sp.SetApp(sp.liabilitiesState)
+	if err != nil {
+		return 0, err
+	}
+
+	return reverted, nil
+}
+
+// pendingEntry returns pending liability entry created at proposed state turn.
+func (sp *StateProposal) pendingEntry(amount decimal.Decimal, reference string, expiry int64) liability.PendingEntry {
+	return liability.PendingEntry{
+		Amount:    amount,
+		Reference: reference,
+		TurnNum:   sp.TurnNum(),
+		Timestamp: time.Now().Unix(),
+		Expiry:    expiry,
+	}
 }
 
 // ExecutedLiability add executed liability to state proposal.
+// Execution is recorded with proposed state turn num and current time.
 func (sp *StateProposal) ExecutedLiability(from, to uint, asset liability.Asset, amount decimal.Decimal) error {
-	return sp.liabilitiesState.AddExecutedLiability(from, to, asset, amount)
+	execution := liability.Execution{
+		Amount:    amount,
+		TurnNum:   sp.TurnNum(),
+		Timestamp: time.Now().Unix(),
+	}
+
+	return sp.liabilitiesState.AddExecutedLiability(from, to, asset, execution)
 }
 
 // RevertLiability add revert liability to state proposal.
@@ -75,14 +197,28 @@ func (sp *StateProposal) RevertLiability(from, to uint, asset liability.Asset, a
 	return sp.liabilitiesState.AddRevertLiability(from, to, asset, amount)
 }
 
+// NetLiabilities replaces pending and executed liabilities of state proposal with multilateral netted ones.
+// Netting takes effect once all participants sign the proposed state.
+func (sp *StateProposal) NetLiabilities() {
+	if !sp.isLiabilitiesApp() {
+		return
+	}
+
+	sp.setApp(sp.liabilitiesState.Net())
+}
+
 // ApproveLiabilities approves all requested liabilities.
+// If the channel has an asset registry, liabilities are validated against assets in the outcome.
+// If the channel has a risk policy, liabilities exceeding collateral ratio are rejected.
 func (sp *StateProposal) ApproveLiabilities() error {
-	appDataBytes, err := sp.liabilitiesState.EncodeToBytes()
+	if !sp.isLiabilitiesApp() {
+		return ErrLiabilitiesNotSupported
+	}
+
+	err := checkLiabilities(sp.liabilitiesState, sp.Outcome(), sp.assets, sp.risk)
 	if err != nil {
 		return err
 	}
 
-	sp.state.AppData = appDataBytes
-
-	return nil
+	return sp.SetApp(sp.liabilitiesState)
 }
@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"app/internal/asset"
 	"app/internal/liability"
 	"app/pkg/nitro"
 	"math/big"
@@ -10,6 +11,7 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/statechannels/go-nitro/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func getStateProposal() (StateProposal, error) {
@@ -25,7 +27,7 @@ func getStateProposal() (StateProposal, error) {
 func TestSetFinal(t *testing.T) {
 	stateProposal, err := getStateProposal()
 	assert.NoError(t, err)
-	stateProposal.SetFinal()
+	assert.NoError(t, stateProposal.SetFinal())
 
 	assert.Equal(t, true, stateProposal.IsFinal())
 }
@@ -48,8 +50,8 @@ func TestAddLiability(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, types.Bytes(types.Bytes{}), stateProposal.AppData())
 
-		stateProposal.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(2))
-		stateProposal.ApproveLiabilities()
+		require.NoError(t, stateProposal.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(2)))
+		require.NoError(t, stateProposal.ApproveLiabilities())
 		assert.NotEmpty(t, stateProposal.AppData())
 	})
 
@@ -58,9 +60,9 @@ func TestAddLiability(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, types.Bytes(types.Bytes{}), stateProposal.AppData())
 
-		stateProposal.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(2))
-		stateProposal.PendingLiability(0, 1, "GOLD", decimal.NewFromFloat(1))
-		stateProposal.PendingLiability(0, 1, "LTC", decimal.NewFromFloat(2))
+		require.NoError(t, stateProposal.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(2)))
+		require.NoError(t, stateProposal.PendingLiability(0, 1, "GOLD", decimal.NewFromFloat(1)))
+		require.NoError(t, stateProposal.PendingLiability(0, 1, "LTC", decimal.NewFromFloat(2)))
 
 		err = stateProposal.ExecutedLiability(0, 1, "LTC", decimal.NewFromFloat(2))
 		assert.NoError(t, err)
@@ -73,7 +75,77 @@ func TestAddLiability(t *testing.T) {
 		liab, err := liability.DecodeFromBytes(stateProposal.AppData())
 		assert.NoError(t, err)
 
-		assert.Equal(t, map[liability.Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, liab[0][1].Pending)
-		assert.Equal(t, map[liability.Asset]decimal.Decimal{"LTC": decimal.NewFromFloat(2)}, liab[0][1].Executed)
+		assert.Equal(t, map[liability.Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, liab[0][1].PendingAmounts())
+		assert.Equal(t, map[liability.Asset]decimal.Decimal{"LTC": decimal.NewFromFloat(2)}, liab[0][1].ExecutedAmounts())
+		assert.Equal(t, stateProposal.TurnNum(), liab[0][1].Executed["LTC"][0].TurnNum)
 	})
 }
+
+func TestNetLiabilities(t *testing.T) {
+	stateProposal, err := getStateProposal()
+	assert.NoError(t, err)
+
+	require.NoError(t, stateProposal.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(3)))
+	require.NoError(t, stateProposal.PendingLiability(1, 0, "ETH", decimal.NewFromFloat(1)))
+	stateProposal.NetLiabilities()
+
+	err = stateProposal.ApproveLiabilities()
+	assert.NoError(t, err)
+
+	liab, err := liability.DecodeFromBytes(stateProposal.AppData())
+	assert.NoError(t, err)
+	assert.Equal(t, map[liability.Asset]decimal.Decimal{"ETH": decimal.NewFromFloat(2)}, liab[0][1].PendingAmounts())
+	assert.Equal(t, 1, len(liab))
+}
+
+func TestApproveLiabilities(t *testing.T) {
+	assets, err := asset.NewRegistry(asset.Info{Symbol: "ETH", Address: common.HexToAddress("0x"), Decimals: 18})
+	assert.NoError(t, err)
+
+	t.Run("liability asset is not registered", func(t *testing.T) {
+		stateProposal, err := getStateProposal()
+		assert.NoError(t, err)
+		stateProposal.assets = assets
+
+		require.NoError(t, stateProposal.PendingLiability(0, 1, "BTC", decimal.NewFromFloat(1)))
+		err = stateProposal.ApproveLiabilities()
+		assert.ErrorIs(t, err, asset.ErrUnknownAsset)
+		assert.Empty(t, stateProposal.AppData())
+	})
+
+	t.Run("registered liability asset", func(t *testing.T) {
+		stateProposal, err := getStateProposal()
+		assert.NoError(t, err)
+		stateProposal.assets = assets
+
+		require.NoError(t, stateProposal.PendingLiability(0, 1, "ETH", decimal.NewFromFloat(1)))
+		err = stateProposal.ApproveLiabilities()
+		assert.NoError(t, err)
+		assert.NotEmpty(t, stateProposal.AppData())
+	})
+}
+
+func TestLiabilitiesByReference(t *testing.T) {
+	stateProposal, err := getStateProposal()
+	assert.NoError(t, err)
+
+	err = stateProposal.PendingLiabilityWithReference(0, 1, "ETH", decimal.NewFromFloat(3), "order-1", 0)
+	assert.NoError(t, err)
+	err = stateProposal.PendingLiabilityWithReference(1, 0, "ETH", decimal.NewFromFloat(1), "order-1", 0)
+	assert.ErrorIs(t, err, liability.ErrDuplicateReference)
+	err = stateProposal.PendingLiabilityWithReference(1, 0, "BTC", decimal.NewFromFloat(1), "order-2", 0)
+	assert.NoError(t, err)
+
+	err = stateProposal.ExecutedLiabilityByReference("order-1", decimal.NewFromFloat(1))
+	assert.NoError(t, err)
+	err = stateProposal.RevertLiabilityByReference("order-2")
+	assert.NoError(t, err)
+	assert.NoError(t, stateProposal.ApproveLiabilities())
+
+	liab, err := liability.DecodeFromBytes(stateProposal.AppData())
+	assert.NoError(t, err)
+	assert.True(t, liab[0][1].PendingAmount("ETH").Equal(decimal.NewFromFloat(2)))
+	assert.Equal(t, "order-1", liab[0][1].Executed["ETH"][0].Reference)
+	assert.Equal(t, stateProposal.TurnNum(), liab[0][1].Executed["ETH"][0].TurnNum)
+	assert.Empty(t, liab[1])
+}
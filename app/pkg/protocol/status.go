@@ -0,0 +1,104 @@
+package protocol
+
+import (
+	"app/pkg/nitro"
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/statechannels/go-nitro/channel/state"
+)
+
+var ErrNoChainReader = errors.New("channel: chain reader is not set")
+
+// OnChainStatus represents channel status stored by the adjudicator.
+type OnChainStatus struct {
+	TurnNumRecord uint64
+	FinalizesAt   uint64
+	Fingerprint   *big.Int
+	// FingerprintMatch is set when fingerprint was registered with the latest supported state,
+	// either by challenge or by conclusion.
+	FingerprintMatch bool
+	// Timestamp is the timestamp of the latest block, mode is determined with it.
+	Timestamp uint64
+	Mode      nitro.ChannelMode
+}
+
+// OnChainStatus returns channel status stored by the adjudicator and its mode at the latest block.
+func (channel *Channel) OnChainStatus(ctx context.Context) (OnChainStatus, error) {
+	client := channel.initProposal.Contract.Client
+	if client.Adjudicator == nil {
+		return OnChainStatus{}, ErrNoAdjudicator
+	}
+	if client.Chain == nil {
+		return OnChainStatus{}, ErrNoChainReader
+	}
+
+	status, err := client.Adjudicator.UnpackStatus(&bind.CallOpts{Context: ctx}, channel.c.Id)
+	if err != nil {
+		return OnChainStatus{}, err
+	}
+
+	header, err := client.Chain.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return OnChainStatus{}, err
+	}
+
+	finalizesAt := status.FinalizesAt.Uint64()
+	match, err := channel.fingerprintMatch(status.Fingerprint)
+	if err != nil {
+		return OnChainStatus{}, err
+	}
+
+	return OnChainStatus{
+		TurnNumRecord:    status.TurnNumRecord.Uint64(),
+		FinalizesAt:      finalizesAt,
+		Fingerprint:      status.Fingerprint,
+		FingerprintMatch: match,
+		Timestamp:        header.Time,
+		Mode:             nitro.Mode(finalizesAt, header.Time),
+	}, nil
+}
+
+// fingerprintMatch returns true if fingerprint is registered with the latest supported state.
+func (channel *Channel) fingerprintMatch(fingerprint *big.Int) (bool, error) {
+	channel.mu.RLock()
+	supported, err := channel.c.LatestSupportedState()
+	channel.mu.RUnlock()
+	if err != nil {
+		return false, nil
+	}
+
+	_, _, found, err := registeredState(fingerprint, supported)
+	return found, err
+}
+
+// registeredState returns state hash and outcome bytes of the state registered with fingerprint.
+// Challenge registers state hash, conclusion registers zero state hash along with the outcome hash.
+func registeredState(fingerprint *big.Int, states ...state.State) ([32]byte, []byte, bool, error) {
+	if fingerprint.Sign() == 0 {
+		return [32]byte{}, nil, false, nil
+	}
+
+	for _, s := range states {
+		outcomeBytes, err := s.Outcome.Encode()
+		if err != nil {
+			return [32]byte{}, nil, false, err
+		}
+		stateHash, err := s.Hash()
+		if err != nil {
+			return [32]byte{}, nil, false, err
+		}
+
+		outcomeHash := crypto.Keccak256Hash(outcomeBytes)
+		for _, hash := range [][32]byte{stateHash, {}} {
+			if nitro.Fingerprint(hash, outcomeHash).Cmp(fingerprint) == 0 {
+				return hash, outcomeBytes, true, nil
+			}
+		}
+	}
+
+	return [32]byte{}, nil, false, nil
+}
@@ -0,0 +1,93 @@
+package protocol
+
+import (
+	"app/pkg/nitro"
+	"app/pkg/nitro/mock"
+	"app/pkg/nitro/simulated"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnChainStatus(t *testing.T) {
+	t.Run("adjudicator is not set", func(t *testing.T) {
+		ch, err := getChannel()
+		require.NoError(t, err)
+
+		_, err = ch.OnChainStatus(context.Background())
+		assert.ErrorIs(t, err, ErrNoAdjudicator)
+	})
+
+	t.Run("chain reader is not set", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		tc.channel.initProposal.Contract.Client.Chain = nil
+
+		_, err := tc.channel.OnChainStatus(context.Background())
+		assert.ErrorIs(t, err, ErrNoChainReader)
+	})
+
+	tc := getMockChannel(t, 5, 3)
+
+	status, err := tc.channel.OnChainStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, nitro.Open, status.Mode)
+	assert.False(t, status.FingerprintMatch)
+	assert.Equal(t, tc.adjudicator.Time(), status.Timestamp)
+
+	signatures := tc.trade(t, 1, false)
+	tc.challenge(t, 0, signatures)
+
+	status, err = tc.channel.OnChainStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, nitro.Challenge, status.Mode)
+	assert.Equal(t, tc.channel.lastState.TurnNum, status.TurnNumRecord)
+	assert.Equal(t, status.Timestamp+tc.channel.lastState.ChallengeDuration.Uint64(), status.FinalizesAt)
+	assert.True(t, status.FingerprintMatch)
+
+	t.Run("challenge with stale state", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 1, false)
+		tc.challenge(t, 0, signatures)
+		tc.trade(t, 1, false)
+
+		status, err := tc.channel.OnChainStatus(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, nitro.Challenge, status.Mode)
+		assert.False(t, status.FingerprintMatch)
+	})
+
+	tc.adjudicator.IncreaseTime(time.Duration(tc.channel.lastState.ChallengeDuration.Int64()) * time.Second)
+
+	status, err = tc.channel.OnChainStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, nitro.Finalized, status.Mode)
+	assert.True(t, status.FingerprintMatch)
+}
+
+func TestOnChainStatusConcluded(t *testing.T) {
+	// second participant doesn't deposit, its allocation stays in the channel after conclusion
+	tc := newTestParticipants(t, 5, 3)
+	tc.adjudicator = mock.NewAdjudicator(simulated.ChainID)
+	tc.init(t, nitro.Client{Adjudicator: tc.adjudicator, ChainID: simulated.ChainID, Chain: tc.adjudicator, Events: tc.adjudicator})
+	tc.fund(t, mined, tc.participants[0])
+
+	signatures := tc.trade(t, 1, true)
+	_, err := tc.channel.Conclude(context.Background(), tc.participants[0], tc.keys[0], signatures)
+	require.NoError(t, err)
+
+	status, err := tc.channel.OnChainStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, nitro.Finalized, status.Mode)
+	assert.Equal(t, status.Timestamp, status.FinalizesAt)
+	assert.Zero(t, status.TurnNumRecord)
+}
+
+func TestMode(t *testing.T) {
+	assert.Equal(t, nitro.Open, nitro.Mode(0, 10))
+	assert.Equal(t, nitro.Challenge, nitro.Mode(11, 10))
+	assert.Equal(t, nitro.Finalized, nitro.Mode(10, 10))
+	assert.Equal(t, "Challenge", nitro.Challenge.String())
+}
@@ -1,6 +1,8 @@
 package protocol
 
 import (
+	"app/pkg/eth/gasprice"
+	"context"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -31,3 +33,14 @@ func signTransaction(chainID *big.Int, privateKey []byte) (signerFn bind.SignerF
 
 	return
 }
+
+// transactOpts returns options of the participant transaction signed with private key.
+// Gas price and gas limit are estimated unless gas station is supplied.
+func transactOpts(ctx context.Context, chainID *big.Int, p *Participant, privateKey []byte, opts ...gasprice.Station) *bind.TransactOpts {
+	signerFn := signTransaction(chainID, privateKey)
+	if len(opts) == 0 {
+		return &bind.TransactOpts{From: p.Address, Signer: signerFn, Context: ctx}
+	}
+
+	return &bind.TransactOpts{GasPrice: opts[0].GasPrice, GasLimit: opts[0].GasLimit, From: p.Address, Signer: signerFn, Context: ctx}
+}
@@ -0,0 +1,176 @@
+package protocol
+
+import (
+	"app/internal/liability"
+	"app/pkg/nitro"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/statechannels/go-nitro/channel/state"
+)
+
+var (
+	ErrInvalidTransition = errors.New("channel: invalid state transition")
+	ErrNoAdjudicator     = errors.New("channel: adjudicator is not set")
+)
+
+// validateTransition checks that proposed state is a valid transition from the latest supported state.
+// Structural checks, app codec checks and liabilities checks are done locally, app validTransition is called on-chain
+// if contract requires it.
+func (channel *Channel) validateTransition(ctx context.Context, proposed *state.State) error {
+	supported, err := channel.c.LatestSupportedState()
+	if err != nil {
+		return ErrIncompleteState
+	}
+
+	err = validTransition(supported, *proposed, channel.codec())
+	if err != nil {
+		return err
+	}
+
+	err = channel.validateLiabilities(*proposed)
+	if err != nil {
+		return err
+	}
+
+	contract := channel.initProposal.Contract
+	if !contract.ValidateOnChain {
+		return nil
+	}
+
+	return validTransitionOnChain(ctx, contract.Client.Adjudicator, supported, *proposed)
+}
+
+// validateLiabilities checks liabilities in app data of the state against asset registry and risk policy of the contract,
+// so a state with liabilities which weren't approved isn't signed or accepted. Risk isn't checked for final states,
+// their executed liabilities are already settled into the outcome.
+func (channel *Channel) validateLiabilities(s state.State) error {
+	contract := channel.initProposal.Contract
+	if contract.Assets == nil && contract.Risk == nil {
+		return nil
+	}
+
+	app, err := channel.codec().Decode(s.AppData)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+	}
+
+	liabilitiesState, ok := app.(liability.LiabilitiesState)
+	if !ok {
+		return nil
+	}
+
+	policy := contract.Risk
+	if s.IsFinal {
+		policy = nil
+	}
+
+	return checkLiabilities(liabilitiesState, s.Outcome, contract.Assets, policy)
+}
+
+// validTransition checks that fixed part and outcome totals are unchanged, turn num is incremented by one
+// and app codec allows transition between app data of the states and between states if it is a StateValidator.
+func validTransition(from, to state.State, codec AppCodec) error {
+	if from.IsFinal {
+		return fmt.Errorf("%w: state %d is final", ErrInvalidTransition, from.TurnNum)
+	}
+
+	if to.TurnNum != from.TurnNum+1 {
+		return fmt.Errorf("%w: turn num %d doesn't follow turn num %d", ErrInvalidTransition, to.TurnNum, from.TurnNum)
+	}
+
+	if to.ChainId.Cmp(from.ChainId) != 0 {
+		return fmt.Errorf("%w: chain id changed from %s to %s", ErrInvalidTransition, from.ChainId, to.ChainId)
+	}
+
+	if to.ChannelNonce.Cmp(from.ChannelNonce) != 0 {
+		return fmt.Errorf("%w: channel nonce changed from %s to %s", ErrInvalidTransition, from.ChannelNonce, to.ChannelNonce)
+	}
+
+	if len(to.Participants) != len(from.Participants) {
+		return fmt.Errorf("%w: number of participants changed", ErrInvalidTransition)
+	}
+	for i, participant := range from.Participants {
+		if to.Participants[i] != participant {
+			return fmt.Errorf("%w: participant %d changed", ErrInvalidTransition, i)
+		}
+	}
+
+	if to.AppDefinition != from.AppDefinition {
+		return fmt.Errorf("%w: app definition changed", ErrInvalidTransition)
+	}
+
+	if to.ChallengeDuration.Cmp(from.ChallengeDuration) != 0 {
+		return fmt.Errorf("%w: challenge duration changed", ErrInvalidTransition)
+	}
+
+	if !to.Outcome.TotalAllocated().Equal(from.Outcome.TotalAllocated()) {
+		return fmt.Errorf("%w: outcome totals changed", ErrInvalidTransition)
+	}
+
+	fromApp, err := codec.Decode(from.AppData)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+	}
+
+	toApp, err := codec.Decode(to.AppData)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+	}
+
+	err = codec.ValidTransition(fromApp, toApp)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+	}
+
+	if validator, ok := codec.(StateValidator); ok {
+		err = validator.ValidStateTransition(from, to)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+		}
+	}
+
+	return nil
+}
+
+// validTransitionOnChain calls validTransition of the app through the adjudicator.
+func validTransitionOnChain(ctx context.Context, adjudicator nitro.StateChannelContract, from, to state.State) error {
+	if adjudicator == nil {
+		return ErrNoAdjudicator
+	}
+
+	variableParts := [2]nitro.IForceMoveAppVariablePart{}
+	for i, s := range []state.State{from, to} {
+		outcomeBytes, err := s.Outcome.Encode()
+		if err != nil {
+			return err
+		}
+
+		variableParts[i] = nitro.IForceMoveAppVariablePart{Outcome: outcomeBytes, AppData: s.AppData}
+	}
+
+	valid, err := adjudicator.ValidTransition(
+		&bind.CallOpts{Context: ctx},
+		big.NewInt(int64(len(to.Participants))),
+		[2]bool{from.IsFinal, to.IsFinal},
+		variableParts,
+		new(big.Int).SetUint64(to.TurnNum),
+		to.AppDefinition,
+	)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return fmt.Errorf("%w: %v", ErrInvalidTransition, err)
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: rejected by app %s", ErrInvalidTransition, to.AppDefinition)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package protocol
+
+import (
+	"app/internal/asset"
+	"app/internal/liability"
+	"app/pkg/nitro"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/statechannels/go-nitro/channel/state/outcome"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validTransitionAdjudicator is an adjudicator which answers validTransition calls with the given result.
+type validTransitionAdjudicator struct {
+	nitro.StateChannelContract
+	valid bool
+	err   error
+}
+
+func (a validTransitionAdjudicator) ValidTransition(opts *bind.CallOpts, nParticipants *big.Int, isFinalAB [2]bool, ab [2]nitro.IForceMoveAppVariablePart, turnNumB *big.Int, appDefinition common.Address) (bool, error) {
+	return a.valid, a.err
+}
+
+var errRejectedTransition = errors.New("app: transition is rejected")
+
+// rejectingCodec is the liabilities codec which rejects every transition.
+type rejectingCodec struct {
+	LiabilitiesCodec
+}
+
+func (rejectingCodec) ValidTransition(from, to interface{}) error {
+	return errRejectedTransition
+}
+
+func getFundedChannel(t *testing.T) *Channel {
+	ch, err := getChannel()
+	assert.NoError(t, err)
+
+	keys := [][]byte{
+		common.Hex2Bytes("de9be858da4a475276426320d5e9262ecfc3ba460bfac56360bfa6c4c28b4ee0"),
+		common.Hex2Bytes("df57089febbacf7ba0bc227dafbffa9fc08a93fdc68e1e42411a14efcf23656e"),
+	}
+	for _, key := range keys {
+		_, err := ch.ApproveInitChannel(key)
+		assert.NoError(t, err)
+	}
+	for _, key := range keys {
+		_, err := ch.ApproveChannelFunding(key)
+		assert.NoError(t, err)
+	}
+
+	return ch
+}
+
+func TestValidTransition(t *testing.T) {
+	ch := getFundedChannel(t)
+	from, err := ch.c.LatestSupportedState()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(s *state.State)
+		valid  bool
+	}{
+		{"valid transition", func(s *state.State) {}, true},
+		{"turn num is not incremented", func(s *state.State) { s.TurnNum = from.TurnNum }, false},
+		{"turn num is skipped", func(s *state.State) { s.TurnNum = from.TurnNum + 2 }, false},
+		{"chain id changed", func(s *state.State) { s.ChainId = big.NewInt(3) }, false},
+		{"channel nonce changed", func(s *state.State) { s.ChannelNonce = big.NewInt(1) }, false},
+		{"participant changed", func(s *state.State) { s.Participants = []common.Address{s.Participants[1], s.Participants[0]} }, false},
+		{"challenge duration changed", func(s *state.State) { s.ChallengeDuration = big.NewInt(1) }, false},
+		{"outcome total changed", func(s *state.State) { s.Outcome[0].Allocations[0].Amount = big.NewInt(5) }, false},
+		{"allocations moved", func(s *state.State) {
+			s.Outcome[0].Allocations[0].Amount = big.NewInt(1)
+			s.Outcome[0].Allocations[1].Amount = big.NewInt(3)
+		}, false},
+		{"invalid app data", func(s *state.State) { s.AppData = []byte{1, 2, 3} }, false},
+		{"liability of unknown participant", func(s *state.State) {
+			ls := make(liability.LiabilitiesState)
+			ls.AddPendingLiability(0, 2, "ETH", decimal.NewFromFloat(1))
+			s.AppData, _ = ls.EncodeToBytes()
+		}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			to := from.Clone()
+			to.Outcome = from.Outcome.Clone()
+			to.TurnNum = from.TurnNum + 1
+			test.modify(&to)
+
+			err := validTransition(from, to, LiabilitiesCodec{})
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidTransition)
+			}
+		})
+	}
+
+	t.Run("transition from final state", func(t *testing.T) {
+		final := from.Clone()
+		final.IsFinal = true
+		to := from.Clone()
+		to.TurnNum = from.TurnNum + 1
+
+		err := validTransition(final, to, LiabilitiesCodec{})
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+	})
+}
+
+func TestSignStateValidation(t *testing.T) {
+	key := common.Hex2Bytes("de9be858da4a475276426320d5e9262ecfc3ba460bfac56360bfa6c4c28b4ee0")
+
+	t.Run("invalid proposal is not signed", func(t *testing.T) {
+		ch := getFundedChannel(t)
+		sp, err := ch.ProposeState()
+		assert.NoError(t, err)
+
+		sp.state.Outcome[0].Allocations[0].Amount = big.NewInt(10)
+		_, err = ch.SignState(context.Background(), sp, key)
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+		_, found := ch.c.SignedStateForTurnNum[sp.TurnNum()]
+		assert.False(t, found)
+	})
+
+	t.Run("codec rejects transition", func(t *testing.T) {
+		ch := getFundedChannel(t)
+		ch.initProposal.Codec = rejectingCodec{}
+
+		sp, err := ch.ProposeState()
+		assert.NoError(t, err)
+
+		_, err = ch.SignState(context.Background(), sp, key)
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+		assert.Contains(t, err.Error(), errRejectedTransition.Error())
+	})
+
+	t.Run("final outcome is checked against settlement", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			modify func(exit outcome.Exit)
+			valid  bool
+		}{
+			{"settled outcome", func(exit outcome.Exit) {}, true},
+			{"tampered outcome", func(exit outcome.Exit) {
+				exit[0].Allocations[0].Amount = big.NewInt(0)
+				exit[0].Allocations[1].Amount = big.NewInt(4)
+			}, false},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				ch := getFundedChannel(t)
+				assets, err := asset.NewRegistry(asset.Info{Symbol: "ETH", Address: common.Address{}, Decimals: 0})
+				require.NoError(t, err)
+				ch.initProposal.Contract.Assets = assets
+
+				sp, err := ch.ProposeState()
+				require.NoError(t, err)
+				require.NoError(t, sp.PendingLiability(0, 1, "ETH", decimal.NewFromInt(1)))
+				require.NoError(t, sp.ExecutedLiability(0, 1, "ETH", decimal.NewFromInt(1)))
+				require.NoError(t, sp.ApproveLiabilities())
+				require.NoError(t, sp.SetFinal())
+				exit := sp.Outcome()
+				test.modify(exit)
+				sp.SetOutcome(exit)
+
+				_, err = ch.SignState(context.Background(), sp, key)
+				if test.valid {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, ErrInvalidTransition)
+					assert.Contains(t, err.Error(), ErrUnsettledOutcome.Error())
+				}
+			})
+		}
+	})
+
+	t.Run("on-chain validation", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			adjudicator nitro.StateChannelContract
+			err         error
+		}{
+			{"valid transition", validTransitionAdjudicator{valid: true}, nil},
+			{"rejected transition", validTransitionAdjudicator{valid: false}, ErrInvalidTransition},
+			{"call failure", validTransitionAdjudicator{err: errors.New("execution reverted")}, ErrInvalidTransition},
+			{"no adjudicator", nil, ErrNoAdjudicator},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				ch := getFundedChannel(t)
+				ch.initProposal.Contract.ValidateOnChain = true
+				ch.initProposal.Contract.Client.Adjudicator = test.adjudicator
+
+				sp, err := ch.ProposeState()
+				assert.NoError(t, err)
+
+				_, err = ch.SignState(context.Background(), sp, key)
+				if test.err == nil {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, test.err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	chl "github.com/statechannels/go-nitro/channel"
+	"github.com/statechannels/go-nitro/channel/state"
+)
+
+var (
+	ErrNotMover       = errors.New("channel: participant is not the mover of the turn")
+	ErrMoverNotSigned = errors.New("channel: state isn't signed by the mover of the turn")
+)
+
+// mover returns index of the participant who moves at the turn, ForceMove expects turnNum mod N.
+func (channel *Channel) mover(turnNum uint64) uint {
+	return uint(turnNum % uint64(len(channel.c.Participants)))
+}
+
+// turnTaking returns true if turn taking is enforced for the state, setup states are signed in any order.
+func (channel *Channel) turnTaking(turnNum uint64) bool {
+	return channel.initProposal.TurnTaking && turnNum > chl.PostFundTurnNum
+}
+
+// checkProposer returns ErrNotMover if turn taking is enforced and channel owner isn't the mover of the turn.
+func (channel *Channel) checkProposer(turnNum uint64) error {
+	if !channel.turnTaking(turnNum) || channel.mover(turnNum) == channel.c.MyIndex {
+		return nil
+	}
+
+	return fmt.Errorf("%w: turn %d is moved by participant %d", ErrNotMover, turnNum, channel.mover(turnNum))
+}
+
+// checkMoverSigned returns ErrMoverNotSigned if turn taking is enforced, signer isn't the mover
+// and the mover hasn't signed the state yet.
+// It must be called with the channel lock held.
+func (channel *Channel) checkMoverSigned(s *state.State, signer common.Address) error {
+	if !channel.turnTaking(s.TurnNum) {
+		return nil
+	}
+
+	mover := channel.mover(s.TurnNum)
+	if channel.c.Participants[mover] == signer {
+		return nil
+	}
+
+	known, found := channel.c.SignedStateForTurnNum[s.TurnNum]
+	if found && known.State().Equal(*s) && known.HasSignatureForParticipant(mover) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: participant %d signs turn %d first", ErrMoverNotSigned, mover, s.TurnNum)
+}
+
+// addressOf returns address of the private key owner.
+func addressOf(privateKey []byte) (common.Address, error) {
+	key, err := crypto.ToECDSA(privateKey)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(key.PublicKey), nil
+}
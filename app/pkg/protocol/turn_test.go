@@ -0,0 +1,90 @@
+package protocol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/statechannels/go-nitro/channel/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// turnTakingChannels returns funded channel with turn taking enabled and channel of the second participant
+// which has the same setup states.
+func turnTakingChannels(t *testing.T) (testChannel, *Channel) {
+	tc := getMockChannel(t, 5, 3)
+	tc.channel.initProposal.TurnTaking = true
+
+	counterparty, err := InitChannel(tc.channel.initProposal, 1)
+	require.NoError(t, err)
+	for _, s := range []state.State{tc.channel.c.PreFundState(), tc.channel.c.PostFundState()} {
+		for _, key := range tc.keys {
+			require.NoError(t, counterparty.AddSignature(&s, signatureOf(t, s, key)))
+		}
+	}
+
+	return tc, counterparty
+}
+
+func TestTurnTaking(t *testing.T) {
+	t.Run("mover proposes and signs first", func(t *testing.T) {
+		tc, _ := turnTakingChannels(t)
+
+		sp, err := tc.channel.ProposeState()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), sp.TurnNum())
+
+		_, err = tc.channel.SignState(context.Background(), sp, tc.keys[1])
+		assert.ErrorIs(t, err, ErrMoverNotSigned)
+
+		_, err = tc.channel.SignState(context.Background(), sp, tc.keys[0])
+		require.NoError(t, err)
+		_, err = tc.channel.SignState(context.Background(), sp, tc.keys[1])
+		require.NoError(t, err)
+	})
+
+	t.Run("only mover proposes", func(t *testing.T) {
+		tc, counterparty := turnTakingChannels(t)
+		require.NoError(t, transfer(tc.channel, tc.keys, 1))
+
+		_, err := tc.channel.ProposeState()
+		assert.ErrorIs(t, err, ErrNotMover)
+		assert.Equal(t, uint64(2), tc.channel.CurrentState().TurnNum)
+
+		s := tc.channel.CurrentState()
+		for _, key := range tc.keys {
+			require.NoError(t, counterparty.AddSignature(&s, signatureOf(t, s, key)))
+		}
+
+		sp, err := counterparty.ProposeState()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), sp.TurnNum())
+	})
+
+	t.Run("received signatures", func(t *testing.T) {
+		tc, counterparty := turnTakingChannels(t)
+		s := nextTurnState(tc.channel, 1)
+
+		err := counterparty.AddSignature(&s, signatureOf(t, s, tc.keys[1]))
+		assert.ErrorIs(t, err, ErrMoverNotSigned)
+
+		require.NoError(t, counterparty.AddSignature(&s, signatureOf(t, s, tc.keys[0])))
+		require.NoError(t, counterparty.AddSignature(&s, signatureOf(t, s, tc.keys[1])))
+		supported, err := counterparty.c.LatestSupportedState()
+		require.NoError(t, err)
+		assert.Equal(t, s.TurnNum, supported.TurnNum)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		sp, err := tc.channel.ProposeState()
+		require.NoError(t, err)
+
+		_, err = tc.channel.SignState(context.Background(), sp, tc.keys[1])
+		assert.NoError(t, err)
+
+		// turn 3 is moved by the second participant
+		s := nextTurnState(tc.channel, 1)
+		assert.NoError(t, tc.channel.AddSignature(&s, signatureOf(t, s, tc.keys[0])))
+	})
+}
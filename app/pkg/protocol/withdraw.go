@@ -0,0 +1,68 @@
+package protocol
+
+import (
+	"app/pkg/eth/gasprice"
+	"app/pkg/nitro"
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/statechannels/go-nitro/channel/state"
+)
+
+var (
+	ErrNotFinalized       = errors.New("channel: channel is not finalized on-chain")
+	ErrUnknownFingerprint = errors.New("channel: on-chain fingerprint doesn't match any signed state")
+)
+
+// WithdrawAfterFinalization transfers all assets of the channel finalized on-chain, e.g. after challenge timed out.
+// Outcome and state hash registered by the adjudicator are the outcome left after the latest payout
+// or are reconstructed from the signed states, an error is thrown if none of them matches the on-chain fingerprint.
+func (channel *Channel) WithdrawAfterFinalization(ctx context.Context, p *Participant, privateKey []byte, opts ...gasprice.Station) (*types.Transaction, error) {
+	status, err := channel.OnChainStatus(ctx)
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+
+	if status.Mode != nitro.Finalized {
+		return &types.Transaction{}, ErrNotFinalized
+	}
+
+	registered, err := channel.registeredOutcome(status)
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+
+	outcomeBytes, err := registered.exit.Encode()
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+
+	adjudicator := channel.initProposal.Contract.Client.Adjudicator
+	transaction, err := adjudicator.TransferAllAssets(transactOpts(ctx, channel.c.ChainId, p, privateKey, opts...), channel.c.Id, outcomeBytes, registered.stateHash)
+	if err != nil {
+		return &types.Transaction{}, err
+	}
+	channel.closing(transaction)
+
+	return transaction, nil
+}
+
+// signedStates returns signed states which could be registered on-chain, the state of the turn number record goes first.
+// It must be called holding the channel lock.
+func (channel *Channel) signedStates(turnNumRecord uint64) []state.State {
+	var states []state.State
+	if signed, found := channel.c.SignedStateForTurnNum[turnNumRecord]; found {
+		states = append(states, signed.State())
+	}
+
+	if supported, err := channel.c.LatestSupportedState(); err == nil {
+		states = append(states, supported)
+	}
+
+	if channel.lastState != nil {
+		states = append(states, *channel.lastState)
+	}
+
+	return states
+}
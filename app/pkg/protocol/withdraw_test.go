@@ -0,0 +1,77 @@
+package protocol
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithdrawAfterFinalization(t *testing.T) {
+	t.Run("withdraw with the latest state", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 2, false)
+		tc.challenge(t, 1, signatures)
+
+		_, err := tc.channel.WithdrawAfterFinalization(context.Background(), tc.participants[1], tc.keys[1])
+		assert.ErrorIs(t, err, ErrNotFinalized)
+
+		tc.adjudicator.IncreaseTime(time.Duration(tc.channel.lastState.ChallengeDuration.Int64()) * time.Second)
+		_, err = tc.channel.WithdrawAfterFinalization(context.Background(), tc.participants[1], tc.keys[1])
+		require.NoError(t, err)
+
+		assert.Equal(t, big.NewInt(3), tc.adjudicator.Payout(common.Address{}, tc.participants[0].Address))
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+		holdings, err := tc.channel.CheckHoldings(context.Background())
+		assert.NoError(t, err)
+		assert.Zero(t, holdings.Sign())
+	})
+
+	t.Run("withdraw with challenged stale state", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 2, false)
+		tc.challenge(t, 0, signatures)
+		tc.trade(t, 2, false)
+
+		tc.adjudicator.IncreaseTime(time.Duration(tc.channel.lastState.ChallengeDuration.Int64()) * time.Second)
+		_, err := tc.channel.WithdrawAfterFinalization(context.Background(), tc.participants[0], tc.keys[0])
+		require.NoError(t, err)
+
+		assert.Equal(t, big.NewInt(3), tc.adjudicator.Payout(common.Address{}, tc.participants[0].Address))
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+	})
+
+	t.Run("withdraw after partial payout", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+		signatures := tc.trade(t, 2, true)
+		_, err := tc.channel.Finalize(context.Background(), tc.participants[0], tc.keys[0], signatures)
+		require.NoError(t, err)
+
+		tx, err := tc.channel.Payout(context.Background(), tc.participants[1], tc.keys[1], []uint{1})
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+		receipt, err := mined(tx)
+		require.NoError(t, err)
+		tc.channel.ConfirmTransaction(receipt)
+
+		_, err = tc.channel.WithdrawAfterFinalization(context.Background(), tc.participants[0], tc.keys[0])
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(3), tc.adjudicator.Payout(common.Address{}, tc.participants[0].Address))
+		assert.Equal(t, big.NewInt(5), tc.adjudicator.Payout(common.Address{}, tc.participants[1].Address))
+
+		holdings, err := tc.channel.CheckHoldings(context.Background())
+		assert.NoError(t, err)
+		assert.Zero(t, holdings.Sign())
+	})
+
+	t.Run("open channel", func(t *testing.T) {
+		tc := getMockChannel(t, 5, 3)
+
+		_, err := tc.channel.WithdrawAfterFinalization(context.Background(), tc.participants[0], tc.keys[0])
+		assert.ErrorIs(t, err, ErrNotFinalized)
+	})
+}
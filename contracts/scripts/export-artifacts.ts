@@ -0,0 +1,76 @@
+const {createHash} = require('crypto');
+const {existsSync, mkdirSync, readFileSync, readdirSync, writeFileSync} = require('fs');
+const path = require('path');
+
+// Contracts deployed by the simulated backend of the Go tests.
+// They are compiled from the Solidity 0.8 port in simulated/, the compiler is pinned to the exact build below.
+const contracts = {
+  NitroAdjudicator: 'contracts/NitroAdjudicator.sol',
+  HashLockedSwap: 'contracts/examples/HashLockedSwap.sol',
+};
+
+const compiler = {
+  version: 'v0.8.21+commit.d9974bed',
+  sha256: '45bea352b41d04039e19439962ddef1d3e10cf2bc9526feba39f2cc79e3c5a17',
+};
+
+const settings = {
+  optimizer: {enabled: true, runs: 200},
+  evmVersion: 'berlin',
+  viaIR: true,
+  outputSelection: {'*': {'*': ['abi', 'evm.bytecode.object']}},
+};
+
+const sourcesPath = __dirname + '/../simulated/';
+const cachePath = __dirname + '/../cache/';
+const outputPath = __dirname + '/../../app/pkg/nitro/simulated/artifacts/';
+
+// readSources returns contents of the port keyed by import path, libraries are imported by package name.
+function readSources() {
+  const sources = {};
+  const walk = (dir, key) => {
+    readdirSync(dir, {withFileTypes: true}).forEach(entry => {
+      const entryKey = key ? key + '/' + entry.name : entry.name;
+      if (entry.isDirectory()) walk(path.join(dir, entry.name), entryKey);
+      else if (entry.name.endsWith('.sol')) sources[entryKey] = {content: readFileSync(path.join(dir, entry.name), 'utf8')};
+    });
+  };
+  walk(sourcesPath + 'contracts', 'contracts');
+  walk(sourcesPath + 'lib', '');
+  return sources;
+}
+
+// loadCompiler downloads the pinned compiler build once and verifies its checksum.
+async function loadCompiler() {
+  const file = cachePath + 'soljson-' + compiler.version + '.js';
+  if (!existsSync(file)) {
+    const response = await fetch('https://binaries.soliditylang.org/bin/soljson-' + compiler.version + '.js');
+    if (!response.ok) throw new Error('compiler download failed: ' + response.status);
+    mkdirSync(cachePath, {recursive: true});
+    writeFileSync(file, Buffer.from(await response.arrayBuffer()));
+  }
+
+  const sha256 = createHash('sha256').update(readFileSync(file)).digest('hex');
+  if (sha256 !== compiler.sha256) throw new Error('compiler checksum mismatch: ' + sha256);
+
+  const soljson = require(path.resolve(file));
+  return soljson.cwrap('solidity_compile', 'string', ['string', 'number', 'number']);
+}
+
+async function main() {
+  const compile = await loadCompiler();
+  const output = JSON.parse(compile(JSON.stringify({language: 'Solidity', sources: readSources(), settings}), 0, 0));
+  const errors = (output.errors || []).filter(e => e.severity === 'error');
+  if (errors.length > 0) throw new Error(errors.map(e => e.formattedMessage).join('\n'));
+
+  Object.entries(contracts).forEach(([name, source]) => {
+    const artifact = output.contracts[source][name];
+    writeFileSync(outputPath + name + '.abi', JSON.stringify(artifact.abi));
+    writeFileSync(outputPath + name + '.bin', artifact.evm.bytecode.object);
+  });
+}
+
+main().catch(err => {
+  console.error(err);
+  process.exit(1);
+});
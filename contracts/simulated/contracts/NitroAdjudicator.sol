@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+pragma experimental ABIEncoderV2;
+
+import './ForceMove.sol';
+import {ExitFormat as Outcome} from '@statechannels/exit-format/contracts/ExitFormat.sol';
+import './MultiAssetHolder.sol';
+
+/**
+ * @dev The NitroAdjudicator contract extends MultiAssetHolder and ForceMove
+ */
+contract NitroAdjudicator is ForceMove, MultiAssetHolder {
+    /**
+     * @notice Finalizes a channel by providing a finalization proof, and liquidates all assets for the channel.
+     * @dev Finalizes a channel by providing a finalization proof, and liquidates all assets for the channel.
+     * @param largestTurnNum The largest turn number of the submitted states; will overwrite the stored value of `turnNumRecord`.
+     * @param fixedPart Data describing properties of the state channel that do not change with state updates.
+     * @param appData Application specific data.
+     * @param outcomeBytes abi.encode of an array of Outcome.OutcomeItem structs.
+     * @param numStates The number of states in the finalization proof.
+     * @param whoSignedWhat An array denoting which participant has signed which state: `participant[i]` signed the state with index `whoSignedWhat[i]`.
+     * @param sigs Array of signatures, one for each participant, in participant order (e.g. [sig of participant[0], sig of participant[1], ...]).
+     */
+    function concludeAndTransferAllAssets(
+        uint48 largestTurnNum,
+        FixedPart memory fixedPart,
+        bytes memory appData,
+        bytes memory outcomeBytes,
+        uint8 numStates,
+        uint8[] memory whoSignedWhat,
+        Signature[] memory sigs
+    ) public {
+        bytes32 channelId = _conclude(
+            largestTurnNum,
+            fixedPart,
+            appData,
+            outcomeBytes,
+            numStates,
+            whoSignedWhat,
+            sigs
+        );
+
+        transferAllAssets(channelId, outcomeBytes, bytes32(0));
+    }
+
+    /**
+     * @notice Liquidates all assets for the channel
+     * @dev Liquidates all assets for the channel
+     * @param channelId Unique identifier for a state channel
+     * @param outcomeBytes abi.encode of an array of Outcome.OutcomeItem structs.
+     * @param stateHash stored state hash for the channel
+     */
+    function transferAllAssets(
+        bytes32 channelId,
+        bytes memory outcomeBytes,
+        bytes32 stateHash
+    ) public {
+        // checks
+        _requireChannelFinalized(channelId);
+        _requireMatchingFingerprint(stateHash, keccak256(outcomeBytes), channelId);
+
+        // computation
+        bool allocatesOnlyZerosForAllAssets = true;
+        Outcome.SingleAssetExit[] memory outcome = Outcome.decodeExit(outcomeBytes);
+        Outcome.SingleAssetExit[] memory exit = new Outcome.SingleAssetExit[](outcome.length);
+        uint256[] memory initialHoldings = new uint256[](outcome.length);
+        uint256[] memory totalPayouts = new uint256[](outcome.length);
+        for (uint256 assetIndex = 0; assetIndex < outcome.length; assetIndex++) {
+            Outcome.SingleAssetExit memory assetOutcome = outcome[assetIndex];
+            Outcome.Allocation[] memory allocations = assetOutcome.allocations;
+            address asset = outcome[assetIndex].asset;
+            initialHoldings[assetIndex] = holdings[asset][channelId];
+            (
+                Outcome.Allocation[] memory newAllocations,
+                bool allocatesOnlyZeros,
+                Outcome.Allocation[] memory exitAllocations,
+                uint256 totalPayoutsForAsset
+            ) = compute_transfer_effects_and_interactions(
+                initialHoldings[assetIndex],
+                allocations,
+                new uint256[](0)
+            );
+            if (!allocatesOnlyZeros) allocatesOnlyZerosForAllAssets = false;
+            totalPayouts[assetIndex] = totalPayoutsForAsset;
+            outcome[assetIndex].allocations = newAllocations;
+            exit[assetIndex] = Outcome.SingleAssetExit(
+                asset,
+                assetOutcome.metadata,
+                exitAllocations
+            );
+        }
+
+        // effects
+        for (uint256 assetIndex = 0; assetIndex < outcome.length; assetIndex++) {
+            address asset = outcome[assetIndex].asset;
+            holdings[asset][channelId] -= totalPayouts[assetIndex];
+            emit AllocationUpdated(channelId, assetIndex, initialHoldings[assetIndex]);
+        }
+
+        if (allocatesOnlyZerosForAllAssets) {
+            delete statusOf[channelId];
+        } else {
+            bytes32 outcomeHash = keccak256(abi.encode(outcomeBytes));
+            _updateFingerprint(channelId, stateHash, outcomeHash);
+        }
+
+        // interactions
+        _executeExit(exit);
+    }
+
+    /**
+    * @notice Check that the submitted pair of states form a valid transition (public wrapper for internal function _requireValidTransition)
+    * @dev Check that the submitted pair of states form a valid transition (public wrapper for internal function _requireValidTransition)
+    * @param nParticipants Number of participants in the channel.
+    transition
+    * @param isFinalAB Pair of booleans denoting whether the first and second state (resp.) are final.
+    * @param ab Variable parts of each of the pair of states
+    * @param turnNumB turnNum of the later state of the pair.
+    * @param appDefinition Address of deployed contract containing application-specific validTransition function.
+    * @return true if the later state is a validTransition from its predecessor, reverts otherwise.
+    */
+    function validTransition(
+        uint256 nParticipants,
+        bool[2] memory isFinalAB, // [a.isFinal, b.isFinal]
+        IForceMoveApp.VariablePart[2] memory ab, // [a,b]
+        uint48 turnNumB,
+        address appDefinition
+    ) public pure returns (bool) {
+        return _requireValidTransition(nParticipants, isFinalAB, ab, turnNumB, appDefinition);
+    }
+
+    /**
+     * @notice Executes an exit by paying out assets and calling external contracts
+     * @dev Executes an exit by paying out assets and calling external contracts
+     * @param exit The exit to be paid out.
+     */
+    function _executeExit(Outcome.SingleAssetExit[] memory exit) internal {
+        for (uint256 assetIndex = 0; assetIndex < exit.length; assetIndex++) {
+            _executeSingleAssetExit(exit[assetIndex]);
+        }
+    }
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+import '@openzeppelin/contracts/token/ERC20/IERC20.sol';
+
+library ExitFormat {
+    struct SingleAssetExit {
+        address asset;
+        bytes metadata;
+        Allocation[] allocations;
+    }
+
+    enum AllocationType {simple, withdrawHelper, guarantee}
+
+    struct Allocation {
+        bytes32 destination;
+        uint256 amount;
+        uint8 allocationType;
+        bytes metadata;
+    }
+
+    function encodeExit(SingleAssetExit[] memory exit) internal pure returns (bytes memory) {
+        return abi.encode(exit);
+    }
+
+    function decodeExit(bytes memory _exit) internal pure returns (SingleAssetExit[] memory) {
+        return abi.decode(_exit, (SingleAssetExit[]));
+    }
+
+    function executeExit(SingleAssetExit[] memory exit) internal {
+        for (uint256 assetIndex = 0; assetIndex < exit.length; assetIndex++) {
+            executeSingleAssetExit(exit[assetIndex]);
+        }
+    }
+
+    function executeSingleAssetExit(SingleAssetExit memory singleAssetExit) internal {
+        address asset = singleAssetExit.asset;
+        for (uint256 j = 0; j < singleAssetExit.allocations.length; j++) {
+            require(_isAddress(singleAssetExit.allocations[j].destination), 'Destination is not a zero-padded address');
+            address payable destination = payable(address(uint160(uint256(singleAssetExit.allocations[j].destination))));
+            uint256 amount = singleAssetExit.allocations[j].amount;
+            if (asset == address(0)) {
+                (bool success, ) = destination.call{value: amount}('');
+                require(success, 'Could not transfer ETH');
+            } else {
+                IERC20(asset).transfer(destination, amount);
+            }
+        }
+    }
+
+    function _isAddress(bytes32 destination) internal pure returns (bool) {
+        return uint96(bytes12(destination)) == 0;
+    }
+}